@@ -0,0 +1,161 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"iter"
+	"math"
+	"math/big"
+)
+
+//Aggregator sums up a stream of amounts in the same currency,
+//the first error (mismatched currency or overflow) is kept and returned by all later calls
+type Aggregator struct {
+	curreny Currency
+	sum     int64 //the sum in minor unit
+	count   int64
+	err     error
+}
+
+//Add add amount to the aggregation
+//return error if the currency of amount is different from the previous ones
+//return error if the sum overflows
+func (aggregator *Aggregator) Add(amount Amount) error {
+	if aggregator.err != nil {
+		return aggregator.err
+	}
+	if amount.curreny.Code() == "" {
+		aggregator.err = errors.New("aggregate fail: amount has no currency")
+		return aggregator.err
+	}
+	if aggregator.count == 0 {
+		aggregator.curreny = amount.curreny
+	} else if aggregator.curreny.Code() != amount.curreny.Code() {
+		aggregator.err = fmt.Errorf("aggregate fail: curreny %s of amount %d is different from %s", amount.curreny.Code(), aggregator.count, aggregator.curreny.Code())
+		return aggregator.err
+	}
+
+	sum, ok := addInt64(aggregator.sum, amount.minorUnitValue)
+	if !ok {
+		aggregator.err = fmt.Errorf("aggregate fail: sum overflows at amount %d", aggregator.count)
+		return aggregator.err
+	}
+	aggregator.sum = sum
+	aggregator.count++
+	return nil
+}
+
+//Count returns the number of aggregated amounts
+func (aggregator *Aggregator) Count() int64 {
+	return aggregator.count
+}
+
+//Sum returns the sum of aggregated amounts
+//return error if no amount is aggregated, or an error occurred while adding
+func (aggregator *Aggregator) Sum() (Amount, error) {
+	if aggregator.err != nil {
+		return Amount{}, aggregator.err
+	}
+	if aggregator.count == 0 {
+		return Amount{}, errors.New("aggregate fail: no amount")
+	}
+	result := newZeroAmount(aggregator.curreny)
+	result.setMinorUnitValue(aggregator.sum)
+	return result, nil
+}
+
+//Average returns the average of aggregated amounts rounded by mode, and the remainder (sum - average * count)
+//return error if mode is invalid
+//return error if no amount is aggregated, or an error occurred while adding
+func (aggregator *Aggregator) Average(mode RoundingMode) (Amount, Amount, error) {
+	if err := mode.validate(); err != nil {
+		return Amount{}, Amount{}, err
+	}
+	if aggregator.err != nil {
+		return Amount{}, Amount{}, aggregator.err
+	}
+	if aggregator.count == 0 {
+		return Amount{}, Amount{}, errors.New("aggregate fail: no amount")
+	}
+
+	average := roundRat(big.NewRat(aggregator.sum, aggregator.count), mode).Int64()
+	remainder := new(big.Int).Mul(big.NewInt(average), big.NewInt(aggregator.count))
+	remainder.Sub(big.NewInt(aggregator.sum), remainder)
+	if !remainder.IsInt64() {
+		return Amount{}, Amount{}, errors.New("aggregate fail: remainder overflows")
+	}
+	averageAmount := newZeroAmount(aggregator.curreny)
+	averageAmount.setMinorUnitValue(average)
+	remainderAmount := newZeroAmount(aggregator.curreny)
+	remainderAmount.setMinorUnitValue(remainder.Int64())
+	return averageAmount, remainderAmount, nil
+}
+
+//Sum returns the sum of amounts
+//return error if amounts is empty, the currencies of amounts are not same, or the sum overflows
+func Sum(amounts []Amount) (Amount, error) {
+	return SumSeq(sliceSeq(amounts))
+}
+
+//SumSeq returns the sum of amounts from the iterator, the iteration stops at the first error
+//return error if seq is empty, the currencies of amounts are not same, or the sum overflows
+func SumSeq(seq iter.Seq[Amount]) (Amount, error) {
+	aggregator := aggregate(seq)
+	return aggregator.Sum()
+}
+
+//Average returns the average of amounts rounded by mode, and the remainder (sum - average * count)
+//return error if mode is invalid
+//return error if amounts is empty, the currencies of amounts are not same, or the sum overflows
+func Average(amounts []Amount, mode RoundingMode) (Amount, Amount, error) {
+	return AverageSeq(sliceSeq(amounts), mode)
+}
+
+//AverageSeq returns the average of amounts from the iterator rounded by mode, and the remainder (sum - average * count)
+//the iteration stops at the first error
+//return error if mode is invalid
+//return error if seq is empty, the currencies of amounts are not same, or the sum overflows
+func AverageSeq(seq iter.Seq[Amount], mode RoundingMode) (Amount, Amount, error) {
+	aggregator := aggregate(seq)
+	return aggregator.Average(mode)
+}
+
+//GroupByCurrency groups amounts by currency code, keeps the order of amounts in each group
+func GroupByCurrency(amounts []Amount) map[string][]Amount {
+	groups := make(map[string][]Amount)
+	for _, amount := range amounts {
+		code := amount.curreny.Code()
+		groups[code] = append(groups[code], amount)
+	}
+	return groups
+}
+
+//aggregate adds amounts from the iterator to a new aggregator until the first error
+func aggregate(seq iter.Seq[Amount]) *Aggregator {
+	aggregator := new(Aggregator)
+	for amount := range seq {
+		if aggregator.Add(amount) != nil {
+			break
+		}
+	}
+	return aggregator
+}
+
+//sliceSeq returns an iterator over amounts
+func sliceSeq(amounts []Amount) iter.Seq[Amount] {
+	return func(yield func(Amount) bool) {
+		for _, amount := range amounts {
+			if !yield(amount) {
+				return
+			}
+		}
+	}
+}
+
+//addInt64 returns a + b, false if it overflows
+func addInt64(a int64, b int64) (int64, bool) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, false
+	}
+	return a + b, true
+}
@@ -0,0 +1,74 @@
+package currency
+
+import (
+	"math"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+}
+
+func TestSum(t *testing.T) {
+	usdAmount1, _ := Factory.NewAmountInBasicUnit("USD", "1.5")
+	usdAmount2, _ := Factory.NewAmountInBasicUnit("USD", "2.25")
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "1")
+
+	//case 1:
+	got, _ := Sum([]Amount{usdAmount1, usdAmount2, usdAmount1})
+	want, _ := Factory.NewAmountInBasicUnit("USD", "5.25")
+	if !want.IsEquals(got) {
+		t.Errorf("Sum() == %s, want %s", got.String(), want.String())
+	}
+
+	//case 2:
+	_, err := Sum([]Amount{usdAmount1, cnyAmount})
+	if err == nil {
+		t.Errorf("Sum() of mismatched currencies should be return an error, but no error return")
+	}
+
+	//case 3:
+	_, err = Sum(nil)
+	if err == nil {
+		t.Errorf("Sum(nil) should be return an error, but no error return")
+	}
+
+	//case 4:
+	aggregator := new(Aggregator)
+	aggregator.Add(Amount{curreny: usdAmount1.curreny, minorUnitValue: math.MaxInt64})
+	err = aggregator.Add(usdAmount1)
+	if err == nil {
+		t.Errorf("Aggregator.Add() overflow should be return an error, but no error return")
+	}
+	aggregator.Add(usdAmount1)
+	if aggregator.Count() != 1 {
+		t.Errorf("Aggregator.Count() after error == %d, want 1", aggregator.Count())
+	}
+}
+
+func TestAverage(t *testing.T) {
+	usdAmount1, _ := Factory.NewAmountInBasicUnit("USD", "1")
+	usdAmount2, _ := Factory.NewAmountInBasicUnit("USD", "0")
+
+	//case 1:
+	average, remainder, _ := Average([]Amount{usdAmount1, usdAmount2, usdAmount2}, RoundHalfEven)
+	if average.String() != "USD 0.33" || remainder.String() != "USD 0.01" {
+		t.Errorf("Average() == %s %s, want USD 0.33 USD 0.01", average.String(), remainder.String())
+	}
+
+	//case 2:
+	average, remainder, _ = Average([]Amount{usdAmount1, usdAmount2, usdAmount2}, RoundUp)
+	if average.String() != "USD 0.34" || remainder.String() != "USD -0.02" {
+		t.Errorf("Average() == %s %s, want USD 0.34 USD -0.02", average.String(), remainder.String())
+	}
+}
+
+func TestGroupByCurrency(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "1")
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "1")
+	groups := GroupByCurrency([]Amount{usdAmount, cnyAmount, usdAmount})
+	if len(groups) != 2 || len(groups["USD"]) != 2 || len(groups["CNY"]) != 1 {
+		t.Errorf("GroupByCurrency() == %v, want 2 USD and 1 CNY", groups)
+	}
+}
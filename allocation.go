@@ -0,0 +1,65 @@
+package currency
+
+import (
+	"errors"
+	"math/big"
+)
+
+//Split splits amount into n parts as equal as possible,
+//the remaining minor units are distributed one by one to the first parts, the parts sum up to amount exactly
+//return error if n is not greater than 0
+func (amount Amount) Split(n int) ([]Amount, error) {
+	if n <= 0 {
+		return nil, errors.New("Amount split fail: n must be greater than 0")
+	}
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return amount.Allocate(ratios...)
+}
+
+//Allocate splits amount by ratios (e.g.: 70, 20, 10),
+//the remaining minor units are distributed one by one to the first parts, the parts sum up to amount exactly
+//return error if ratios is empty, any ratio is negative, or all ratios are 0
+func (amount Amount) Allocate(ratios ...int64) ([]Amount, error) {
+	if len(ratios) == 0 {
+		return nil, errors.New("Amount allocate fail: ratios can't be empty")
+	}
+	total := new(big.Int)
+	for _, ratio := range ratios {
+		if ratio < 0 {
+			return nil, errors.New("Amount allocate fail: ratio can't be negative")
+		}
+		total.Add(total, big.NewInt(ratio))
+	}
+	if total.Sign() == 0 {
+		return nil, errors.New("Amount allocate fail: ratios can't be all 0")
+	}
+
+	values := make([]int64, len(ratios))
+	remainder := amount.minorUnitValue
+	for i, ratio := range ratios {
+		share := new(big.Int).Mul(big.NewInt(amount.minorUnitValue), big.NewInt(ratio))
+		values[i] = share.Quo(share, total).Int64() //truncated toward zero, never exceeds amount
+		remainder -= values[i]
+	}
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(values) {
+		if ratios[i] == 0 {
+			continue
+		}
+		values[i] += step
+		remainder -= step
+	}
+
+	parts := make([]Amount, len(values))
+	for i, value := range values {
+		parts[i] = newZeroAmount(amount.curreny)
+		parts[i].setMinorUnitValue(value)
+	}
+	return parts, nil
+}
@@ -0,0 +1,151 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+//AmortizationMethod is the method of repaying a loan
+type AmortizationMethod uint8
+
+const (
+	//Annuity repays equal payments of principal and interest each period
+	Annuity AmortizationMethod = iota
+	//EqualPrincipal repays equal principal each period with decreasing interest
+	EqualPrincipal
+	//InterestOnly repays interest each period and the whole principal at the last period
+	InterestOnly
+	//Balloon repays equal payments each period and the balloon payment at the last period
+	Balloon
+)
+
+//Loan is a loan to amortize
+type Loan struct {
+	Principal      Amount
+	PeriodRate     float64            //the interest rate per period in percent (e.g.: 0.5 for 6% annual repaid monthly)
+	Periods        int                //the number of periods
+	Method         AmortizationMethod //the method of repayment
+	BalloonPayment Amount             //the principal left to repay at the last period, only used by Balloon
+	Mode           RoundingMode       //the rounding mode of interest and payments
+}
+
+//LoanPayment is the payment of a period, Payment == Principal + Interest
+type LoanPayment struct {
+	Period    int //starts from 1
+	Payment   Amount
+	Principal Amount
+	Interest  Amount
+	Balance   Amount //the principal left after payment
+}
+
+//Schedule returns the payments of all periods, the last payment absorbs rounding drift
+//so that the principals of all payments sum up to the principal of loan exactly
+//return error if principal or periods is not greater than 0, or rate is negative
+//return error if periods is more than 100 years of monthly repayment (1200)
+//return error if method or rounding mode is unknown
+//return error if the balloon payment is not in the currency of principal, or not less than principal
+func (loan Loan) Schedule() ([]LoanPayment, error) {
+	if loan.Principal.minorUnitValue <= 0 {
+		return nil, errors.New("loan principal must be greater than 0")
+	}
+	if loan.Periods <= 0 || loan.Periods > maxLoanPeriods {
+		return nil, fmt.Errorf("loan periods must be between 1 and %d", maxLoanPeriods)
+	}
+	if loan.Method > Balloon {
+		return nil, fmt.Errorf("loan amortization method %d is unknown", loan.Method)
+	}
+	if err := loan.Mode.validate(); err != nil {
+		return nil, err
+	}
+	rate, err := ratFromFloat(loan.PeriodRate)
+	if err != nil {
+		return nil, err
+	}
+	if rate.Sign() < 0 {
+		return nil, errors.New("loan rate can't be negative")
+	}
+	rate.Quo(rate, big.NewRat(100, 1))
+
+	var balloon int64
+	if loan.Method == Balloon {
+		if loan.BalloonPayment.curreny.Code() != loan.Principal.curreny.Code() {
+			return nil, errors.New("balloon payment curreny is not same as principal")
+		}
+		if loan.BalloonPayment.minorUnitValue < 0 || loan.BalloonPayment.minorUnitValue >= loan.Principal.minorUnitValue {
+			return nil, errors.New("balloon payment must be between 0 and principal")
+		}
+		balloon = loan.BalloonPayment.minorUnitValue
+	}
+
+	//the fixed payment (Annuity, Balloon) or principal (EqualPrincipal) per period in minor unit
+	var fixed int64
+	switch loan.Method {
+	case Annuity, Balloon:
+		fixed = roundRat(annuityPayment(loan.Principal.minorUnitValue, balloon, rate, loan.Periods), loan.Mode).Int64()
+	case EqualPrincipal:
+		fixed = roundRat(big.NewRat(loan.Principal.minorUnitValue, int64(loan.Periods)), loan.Mode).Int64()
+	}
+
+	payments := make([]LoanPayment, 0, loan.Periods)
+	balance := loan.Principal.minorUnitValue
+	for period := 1; period <= loan.Periods; period++ {
+		interestRat := new(big.Rat).Mul(big.NewRat(balance, 1), rate)
+		interest := roundRat(interestRat, loan.Mode).Int64()
+
+		var principal int64
+		switch loan.Method {
+		case Annuity, Balloon:
+			principal = fixed - interest
+		case EqualPrincipal:
+			principal = fixed
+		case InterestOnly:
+			principal = 0
+		}
+		if period == loan.Periods || principal > balance {
+			principal = balance
+		}
+		balance -= principal
+		payments = append(payments, LoanPayment{
+			Period:    period,
+			Payment:   loan.newAmount(principal + interest),
+			Principal: loan.newAmount(principal),
+			Interest:  loan.newAmount(interest),
+			Balance:   loan.newAmount(balance),
+		})
+	}
+	return payments, nil
+}
+
+//maxLoanPeriods is the max periods of Loan, 100 years of monthly repayment,
+//the annuity payment is computed by exact rational power whose size grows with periods
+const maxLoanPeriods = 100 * 12
+
+//newAmount returns an amount in the currency of principal
+func (loan Loan) newAmount(minorUnitValue int64) Amount {
+	amount := newZeroAmount(loan.Principal.curreny)
+	amount.setMinorUnitValue(minorUnitValue)
+	return amount
+}
+
+//annuityPayment returns the exact equal payment per period which repays principal except balloon,
+//payment = (principal - balloon / (1+rate)^periods) * rate / (1 - (1+rate)^-periods)
+func annuityPayment(principal int64, balloon int64, rate *big.Rat, periods int) *big.Rat {
+	if rate.Sign() == 0 {
+		return big.NewRat(principal-balloon, int64(periods))
+	}
+	growth := new(big.Rat).Add(big.NewRat(1, 1), rate)
+	growth = ratPow(growth, periods) //(1+rate)^periods
+	//payment = (principal * growth - balloon) * rate / (growth - 1)
+	payment := new(big.Rat).Mul(big.NewRat(principal, 1), growth)
+	payment.Sub(payment, big.NewRat(balloon, 1))
+	payment.Mul(payment, rate)
+	return payment.Quo(payment, growth.Sub(growth, big.NewRat(1, 1)))
+}
+
+//ratPow returns base^exponent
+func ratPow(base *big.Rat, exponent int) *big.Rat {
+	num := new(big.Int).Exp(base.Num(), big.NewInt(int64(exponent)), nil)
+	denom := new(big.Int).Exp(base.Denom(), big.NewInt(int64(exponent)), nil)
+	return new(big.Rat).SetFrac(num, denom)
+}
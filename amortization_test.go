@@ -0,0 +1,79 @@
+package currency
+
+import "testing"
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+}
+
+func sumPrincipals(payments []LoanPayment) string {
+	sum := newZeroAmount(payments[0].Principal.curreny)
+	for _, payment := range payments {
+		sum, _ = sum.Add(payment.Principal)
+	}
+	return sum.String()
+}
+
+func TestAnnuitySchedule(t *testing.T) {
+	principal, _ := Factory.NewAmountInBasicUnit("USD", "1000")
+	payments, _ := Loan{Principal: principal, PeriodRate: 1, Periods: 12}.Schedule()
+
+	//case 1:
+	if len(payments) != 12 || payments[0].Payment.String() != "USD 88.85" || payments[0].Interest.String() != "USD 10.00" {
+		t.Fatalf("Schedule() first payment == %v, want USD 88.85 with interest USD 10.00", payments[0])
+	}
+
+	//case 2:
+	last := payments[11]
+	if sumPrincipals(payments) != "USD 1000.00" || last.Balance.MinorUnitValue() != 0 {
+		t.Errorf("Schedule() principals == %s with last balance %s, want USD 1000.00 with 0", sumPrincipals(payments), last.Balance.String())
+	}
+	if last.Payment.String() != "USD 88.84" {
+		t.Errorf("Schedule() last payment == %s, want USD 88.84", last.Payment.String())
+	}
+}
+
+func TestOtherSchedules(t *testing.T) {
+	principal, _ := Factory.NewAmountInBasicUnit("USD", "1000")
+
+	//case 1:
+	payments, _ := Loan{Principal: principal, Periods: 3, Method: EqualPrincipal}.Schedule()
+	if payments[0].Principal.String() != "USD 333.33" || payments[2].Principal.String() != "USD 333.34" {
+		t.Errorf("Schedule(EqualPrincipal) == %v, want USD 333.33 ... USD 333.34", payments)
+	}
+
+	//case 2:
+	payments, _ = Loan{Principal: principal, PeriodRate: 1, Periods: 3, Method: InterestOnly}.Schedule()
+	if payments[0].Payment.String() != "USD 10.00" || payments[2].Payment.String() != "USD 1010.00" {
+		t.Errorf("Schedule(InterestOnly) == %v, want USD 10.00 ... USD 1010.00", payments)
+	}
+
+	//case 3:
+	balloon, _ := Factory.NewAmountInBasicUnit("USD", "500")
+	payments, _ = Loan{Principal: principal, PeriodRate: 1, Periods: 12, Method: Balloon, BalloonPayment: balloon}.Schedule()
+	if sumPrincipals(payments) != "USD 1000.00" || payments[11].Principal.MinorUnitValue() < 50000 || payments[10].Balance.MinorUnitValue() < 50000 {
+		t.Errorf("Schedule(Balloon) == %v, want principals USD 1000.00 with balloon at last", payments)
+	}
+
+	//case 4:
+	_, err := Loan{Principal: principal, Periods: 0}.Schedule()
+	if err == nil {
+		t.Errorf("Schedule() with 0 periods should be return an error, but no error return")
+	}
+
+	//case 5:
+	_, err = Loan{Principal: principal, Periods: 3, Method: AmortizationMethod(9)}.Schedule()
+	if err == nil {
+		t.Errorf("Schedule() with unknown method should be return an error, but no error return")
+	}
+
+	//case 6:
+	_, err = Loan{Principal: principal, PeriodRate: 1, Periods: 20000}.Schedule()
+	if err == nil {
+		t.Errorf("Schedule() with 20000 periods should be return an error, but no error return")
+	}
+	payments, err = Loan{Principal: principal, PeriodRate: 0.5, Periods: 1200}.Schedule()
+	if err != nil || len(payments) != 1200 || payments[1199].Balance.MinorUnitValue() != 0 {
+		t.Errorf("Schedule() with 1200 periods == %d payments %v, want 1200 payments repaying principal", len(payments), err)
+	}
+}
@@ -1,39 +1,66 @@
 package currency
 
 import (
+	"encoding/json"
 	"errors"
-	"fmt"
 	"math"
 	"strconv"
 	"strings"
 )
 
-//Amount is an amount object of currency
+//Amount is an amount object of currency,
+//only the value in minor unit is stored, so that arithmetic is integer work without heap allocation,
+//the value in basic unit is formatted on demand
 type Amount struct {
 	curreny        Currency
-	basicUnitValue string //the value of amount in currency's basic unit
-	minorUnitValue int64  //the value of amount in currency's minor unit
+	minorUnitValue int64 //the value of amount in currency's minor unit
 }
 
 //newZeroAmount create a new Amount object with Currency property, but zero value
 func newZeroAmount(curreny Currency) Amount {
-	return Amount{curreny, "0", 0}
-}
-
-//setBasicUnitValue set the value of amount in currency's basic unit (e.g: USD，1.5 dollar or 1.50 dollar)
-func (amount *Amount) setBasicUnitValue(floatValue float64) {
-	amount.roundBasicUnitValue(floatValue)
+	return Amount{curreny, 0}
 }
 
 //setMinorUnitValue set the value of amount in currency's minor unit(e.g: USD, 150 cent)
 func (amount *Amount) setMinorUnitValue(value int64) {
-	basicUnitFloatValue := float64(value) / math.Pow10(int(amount.curreny.MinorUnitDigits()))
-	amount.setBasicUnitValue(basicUnitFloatValue)
+	amount.minorUnitValue = value
+}
+
+//basicUnitFloatValue returns the nearest float value of amount in currency's basic unit
+func (amount Amount) basicUnitFloatValue() float64 {
+	return float64(amount.minorUnitValue) / math.Pow10(int(amount.curreny.MinorUnitDigits()))
 }
 
 //BasicUnitValue returns the value of amount in currency's basic unit
 func (amount Amount) BasicUnitValue() string {
-	return amount.basicUnitValue
+	var buffer [32]byte
+	return string(amount.appendBasicUnitValue(buffer[:0]))
+}
+
+//appendBasicUnitValue appends the value of amount in currency's basic unit to dst (e.g: USD 150 cent => 1.50)
+func (amount Amount) appendBasicUnitValue(dst []byte) []byte {
+	magnitude := uint64(amount.minorUnitValue)
+	if amount.minorUnitValue < 0 {
+		dst = append(dst, '-')
+		magnitude = -magnitude
+	}
+	var buffer [20]byte
+	digits := strconv.AppendUint(buffer[:0], magnitude, 10)
+	minorUnitDigits := int(amount.curreny.MinorUnitDigits())
+	if minorUnitDigits == 0 {
+		return append(dst, digits...)
+	}
+	if len(digits) <= minorUnitDigits {
+		dst = append(dst, '0', '.')
+		for i := len(digits); i < minorUnitDigits; i++ {
+			dst = append(dst, '0')
+		}
+		return append(dst, digits...)
+	}
+	point := len(digits) - minorUnitDigits
+	dst = append(dst, digits[:point]...)
+	dst = append(dst, '.')
+	return append(dst, digits[point:]...)
 }
 
 //MinorUnitValue returns the value of amount in currency's minor unit
@@ -48,50 +75,74 @@ func (amount Amount) CurrencyCode() string {
 
 //Add return (amount + other)
 //return error if the currency of two amount are not same
+//return error if the result overflows
 func (amount Amount) Add(other Amount) (Amount, error) {
-	if amount.curreny.Code() != other.curreny.Code() {
+	if amount.curreny.code != other.curreny.code {
 		return Amount{}, errors.New("Amount add fail: curreny are not same")
 	}
 
-	totalValue := amount.minorUnitValue + other.minorUnitValue
-	result := newZeroAmount(amount.curreny)
-	result.setMinorUnitValue(totalValue)
-	return result, nil
+	totalValue, ok := addInt64(amount.minorUnitValue, other.minorUnitValue)
+	if !ok {
+		return Amount{}, errors.New("Amount add fail: value overflows")
+	}
+	return Amount{amount.curreny, totalValue}, nil
 }
 
 //Minus return (amount - other)
 //return error if the currency of two amount are not same
+//return error if the result overflows
 func (amount Amount) Minus(other Amount) (Amount, error) {
-	if amount.curreny.Code() != other.curreny.Code() {
+	if amount.curreny.code != other.curreny.code {
 		return Amount{}, errors.New("Amount minus fail: curreny are not same")
 	}
 
 	totalValue := amount.minorUnitValue - other.minorUnitValue
-	result := newZeroAmount(amount.curreny)
-	result.setMinorUnitValue(totalValue)
-	return result, nil
+	if (other.minorUnitValue > 0 && totalValue > amount.minorUnitValue) || (other.minorUnitValue < 0 && totalValue < amount.minorUnitValue) {
+		return Amount{}, errors.New("Amount minus fail: value overflows")
+	}
+	return Amount{amount.curreny, totalValue}, nil
 }
 
 //Multiply return (amount * factor)
+//the result out of range is saturated to the max or min amount, the result of NaN is 0,
+//use MultiplyChecked to get an error instead
 func (amount Amount) Multiply(factor float64) Amount {
-	basicUnitFloatValue, _ := strconv.ParseFloat(amount.basicUnitValue, 64)
-	basicUnitFloatValue = basicUnitFloatValue * factor
-	result := newZeroAmount(amount.curreny)
-	result.roundBasicUnitValue(basicUnitFloatValue)
+	result, err := amount.MultiplyChecked(factor)
+	if err == nil {
+		return result
+	}
+	result = newZeroAmount(amount.curreny)
+	product := amount.basicUnitFloatValue() * factor
+	if product > 0 {
+		result.setMinorUnitValue(math.MaxInt64)
+	} else if product < 0 {
+		result.setMinorUnitValue(math.MinInt64)
+	}
 	return result
 }
 
+//MultiplyChecked return (amount * factor)
+//return error if factor is NaN or Inf, or the result is out of range
+func (amount Amount) MultiplyChecked(factor float64) (Amount, error) {
+	result := newZeroAmount(amount.curreny)
+	if err := result.roundBasicUnitValue(amount.basicUnitFloatValue() * factor); err != nil {
+		return Amount{}, errors.New("Amount multiply fail: " + err.Error())
+	}
+	return result, nil
+}
+
 //Divide return (amount / factor)
 //return error if factor is 0
+//return error if factor is NaN, or the result is out of range
 func (amount Amount) Divide(factor float64) (Amount, error) {
 	if factor == 0 {
 		return Amount{}, errors.New("Amount divide fail: factor can not be 0")
 	}
 
-	basicUnitFloatValue, _ := strconv.ParseFloat(amount.basicUnitValue, 64)
-	basicUnitFloatValue = basicUnitFloatValue / factor
 	result := newZeroAmount(amount.curreny)
-	result.roundBasicUnitValue(basicUnitFloatValue)
+	if err := result.roundBasicUnitValue(amount.basicUnitFloatValue() / factor); err != nil {
+		return Amount{}, errors.New("Amount divide fail: " + err.Error())
+	}
 	return result, nil
 }
 
@@ -99,6 +150,7 @@ func (amount Amount) Divide(factor float64) (Amount, error) {
 //return error if targetCurrencyCode is not three-letter alphabetic code
 //return error if targetCurrencyCode is not managed by factory
 //return error if rate=0
+//return error if rate is NaN or Inf, or the result is out of range
 func (amount Amount) Fx(targetCurrencyCode string, rate float64) (Amount, error) {
 	targetCurrencyCode = strings.ToUpper(strings.TrimSpace(targetCurrencyCode))
 	if targetCurrencyCode == amount.curreny.Code() {
@@ -112,23 +164,49 @@ func (amount Amount) Fx(targetCurrencyCode string, rate float64) (Amount, error)
 		return Amount{}, err
 	}
 
-	minorUnitDigits := int(amount.curreny.MinorUnitDigits())
-	basicUnitFloatValue := float64(amount.minorUnitValue) / math.Pow10(minorUnitDigits)
-	floatValue := basicUnitFloatValue * rate
 	result := newZeroAmount(targetCurrency)
-	result.roundBasicUnitValue(floatValue)
+	if err := result.roundBasicUnitValue(amount.basicUnitFloatValue() * rate); err != nil {
+		return Amount{}, errors.New("fx fail: " + err.Error())
+	}
 	return result, nil
 }
 
+//FxByRateSource foreign exchange by using the rate provided by source
+//return error if source can't provide the rate
+//return error if targetCurrencyCode is not managed by factory
+func (amount Amount) FxByRateSource(targetCurrencyCode string, source RateSource) (Amount, error) {
+	rate, err := source.Rate(amount.curreny.Code(), targetCurrencyCode)
+	if err != nil {
+		return Amount{}, err
+	}
+	return amount.Fx(targetCurrencyCode, rate)
+}
+
+//FxByRateTable foreign exchange by using the cross rate of table,
+//also returns the path of currency codes used to compute the rate (e.g: [CNY USD JPY])
+//return error if table has no path to targetCurrencyCode
+//return error if targetCurrencyCode is not managed by factory
+func (amount Amount) FxByRateTable(targetCurrencyCode string, table *RateTable) (Amount, []string, error) {
+	rate, path, err := table.CrossRate(amount.curreny.Code(), targetCurrencyCode)
+	if err != nil {
+		return Amount{}, nil, err
+	}
+	result, err := amount.Fx(targetCurrencyCode, rate)
+	if err != nil {
+		return Amount{}, nil, err
+	}
+	return result, path, nil
+}
+
 //IsEquals return true if the currency and value are same, otherwise return false
 func (amount Amount) IsEquals(other Amount) bool {
-	return amount.curreny.Code() == other.curreny.Code() && amount.minorUnitValue == other.minorUnitValue
+	return amount.curreny.code == other.curreny.code && amount.minorUnitValue == other.minorUnitValue
 }
 
 //IsGreatThan return true if amount > other, otherwise return false,
 //return error if the currency of two amount are not same
 func (amount Amount) IsGreatThan(other Amount) (bool, error) {
-	if amount.curreny.Code() != other.curreny.Code() {
+	if amount.curreny.code != other.curreny.code {
 		return false, errors.New("curreny are not same")
 	}
 	result := amount.minorUnitValue > other.minorUnitValue
@@ -137,15 +215,70 @@ func (amount Amount) IsGreatThan(other Amount) (bool, error) {
 
 //String returns default format string of amount(e.g.: USD 1.00)
 func (amount Amount) String() string {
-	return fmt.Sprintf("%s %s", amount.curreny.Code(), amount.basicUnitValue)
+	var buffer [40]byte
+	text := append(buffer[:0], amount.curreny.code...)
+	text = append(text, ' ')
+	return string(amount.appendBasicUnitValue(text))
+}
+
+//amountJSON is the JSON format of amount(e.g.: {"currency":"USD","value":"1.00"})
+type amountJSON struct {
+	Currency string `json:"currency"`
+	Value    string `json:"value"`
 }
 
-//roundBasicUnitValue using banker's rounding algorithm
-func (amount *Amount) roundBasicUnitValue(floatValue float64) {
-	formatStr := fmt.Sprintf("%%0.%df", amount.curreny.MinorUnitDigits())
-	stringValue := fmt.Sprintf(formatStr, floatValue)
-	amount.basicUnitValue = stringValue
-	stringValue = strings.Replace(stringValue, ".", "", 1)
-	intValue, _ := strconv.Atoi(stringValue)
-	amount.minorUnitValue = int64(intValue)
+//MarshalJSON implements json.Marshaler
+func (amount Amount) MarshalJSON() ([]byte, error) {
+	return json.Marshal(amountJSON{amount.curreny.Code(), amount.BasicUnitValue()})
+}
+
+//UnmarshalJSON implements json.Unmarshaler, the currency must be managed by factory
+//JSON null is a no-op, the amount is not changed
+func (amount *Amount) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var value amountJSON
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	result, err := Factory.NewAmountInBasicUnit(value.Currency, value.Value)
+	if err != nil {
+		return err
+	}
+	*amount = result
+	return nil
+}
+
+//roundBasicUnitValue using banker's rounding algorithm on the exact decimal value of floatValue
+//return error if floatValue is NaN or Inf, or the result is out of range, the amount is not changed
+func (amount *Amount) roundBasicUnitValue(floatValue float64) error {
+	if math.IsNaN(floatValue) || math.IsInf(floatValue, 0) {
+		return errors.New("amount value is not a finite value")
+	}
+	var buffer [32]byte
+	text := strconv.AppendFloat(buffer[:0], floatValue, 'f', int(amount.curreny.MinorUnitDigits()), 64)
+	negative := len(text) > 0 && text[0] == '-'
+	if negative {
+		text = text[1:]
+	}
+	limit := uint64(math.MaxInt64)
+	if negative {
+		limit++ //math.MinInt64
+	}
+	var magnitude uint64
+	for _, c := range text {
+		if c == '.' {
+			continue
+		}
+		if magnitude > (limit-uint64(c-'0'))/10 {
+			return errors.New("amount value is out of range")
+		}
+		magnitude = magnitude*10 + uint64(c-'0')
+	}
+	amount.minorUnitValue = int64(magnitude)
+	if negative {
+		amount.minorUnitValue = -amount.minorUnitValue
+	}
+	return nil
 }
@@ -1,11 +1,18 @@
 package currency
 
-import "testing"
-import "strconv"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+)
 
 func init() {
 	Factory.NewCurrency("USD", 2)
 	Factory.NewCurrency("CNY", 2)
+	Factory.NewCurrency("JPY", 0)
+	Factory.NewCurrency("KWD", 3)
 }
 
 func TestAdd(t *testing.T) {
@@ -192,3 +199,239 @@ func TestString(t *testing.T) {
 		t.Errorf("%s String() == %s, want %s", usdAmount.String(), got, want)
 	}
 }
+
+func TestBasicUnitValue(t *testing.T) {
+	cases := []struct {
+		code           string
+		minorUnitValue int64
+		want           string
+	}{
+		{"USD", 0, "0.00"},
+		{"USD", -5, "-0.05"},
+		{"USD", 150, "1.50"},
+		{"JPY", -1503, "-1503"},
+		{"KWD", 1, "0.001"},
+		{"USD", math.MinInt64, "-92233720368547758.08"},
+	}
+	for _, c := range cases {
+		amount, _ := Factory.NewAmountInMinorUnit(c.code, c.minorUnitValue)
+		if got := amount.BasicUnitValue(); got != c.want {
+			t.Errorf("%s %d BasicUnitValue() == %s, want %s", c.code, c.minorUnitValue, got, c.want)
+		}
+	}
+}
+
+func TestAddOverflow(t *testing.T) {
+	//case 1:
+	maxAmount, _ := Factory.NewAmountInMinorUnit("USD", math.MaxInt64)
+	oneCent, _ := Factory.NewAmountInMinorUnit("USD", 1)
+	_, err := maxAmount.Add(oneCent)
+	if err == nil {
+		t.Errorf("%s Add(%s) should be return an error, but no error return", maxAmount.String(), oneCent.String())
+	}
+
+	//case 2:
+	minAmount, _ := Factory.NewAmountInMinorUnit("USD", math.MinInt64)
+	_, err = minAmount.Minus(oneCent)
+	if err == nil {
+		t.Errorf("%s Minus(%s) should be return an error, but no error return", minAmount.String(), oneCent.String())
+	}
+}
+
+func TestArithmeticOutOfRange(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "1.00")
+
+	//case 1: the result is never silently 0
+	_, err := usdAmount.MultiplyChecked(1e300)
+	if err == nil {
+		t.Errorf("%s MultiplyChecked(1e300) should be return an error, but no error return", usdAmount.String())
+	}
+	_, err = usdAmount.MultiplyChecked(math.NaN())
+	if err == nil {
+		t.Errorf("%s MultiplyChecked(NaN) should be return an error, but no error return", usdAmount.String())
+	}
+
+	//case 2:
+	_, err = usdAmount.Divide(1e-300)
+	if err == nil {
+		t.Errorf("%s Divide(1e-300) should be return an error, but no error return", usdAmount.String())
+	}
+	_, err = usdAmount.Divide(math.NaN())
+	if err == nil {
+		t.Errorf("%s Divide(NaN) should be return an error, but no error return", usdAmount.String())
+	}
+
+	//case 3:
+	_, err = usdAmount.Fx("CNY", 1e300)
+	if err == nil {
+		t.Errorf("%s Fx(\"CNY\", 1e300) should be return an error, but no error return", usdAmount.String())
+	}
+	_, err = usdAmount.Fx("CNY", math.Inf(1))
+	if err == nil {
+		t.Errorf("%s Fx(\"CNY\", +Inf) should be return an error, but no error return", usdAmount.String())
+	}
+
+	//case 4:
+	largeAmount, _ := Factory.NewAmountInBasicUnit("USD", "1000000000000")
+	got, err := largeAmount.MultiplyChecked(1000)
+	if err != nil || got.BasicUnitValue() != "1000000000000000.00" {
+		t.Errorf("%s MultiplyChecked(1000) == %s %v, want USD 1000000000000000.00", largeAmount.String(), got.String(), err)
+	}
+
+	//case 5: Multiply saturates instead of panicking
+	if got := usdAmount.Multiply(1e300); got.MinorUnitValue() != math.MaxInt64 {
+		t.Errorf("%s Multiply(1e300) == %s, want the max amount", usdAmount.String(), got.String())
+	}
+	if got := usdAmount.Multiply(math.Inf(-1)); got.MinorUnitValue() != math.MinInt64 {
+		t.Errorf("%s Multiply(-Inf) == %s, want the min amount", usdAmount.String(), got.String())
+	}
+	if got := usdAmount.Multiply(math.NaN()); got.MinorUnitValue() != 0 || got.CurrencyCode() != "USD" {
+		t.Errorf("%s Multiply(NaN) == %s, want USD 0.00", usdAmount.String(), got.String())
+	}
+}
+
+func TestArithmeticAllocations(t *testing.T) {
+	usdAmount1, _ := Factory.NewAmountInBasicUnit("usd", "1.567")
+	usdAmount2, _ := Factory.NewAmountInBasicUnit("usd", "0.43")
+	allocs := testing.AllocsPerRun(100, func() {
+		sum, _ := usdAmount1.Add(usdAmount2)
+		difference, _ := sum.Minus(usdAmount2)
+		product := difference.Multiply(1.5)
+		product.Divide(3)
+		product.IsGreatThan(sum)
+	})
+	if allocs != 0 {
+		t.Errorf("Add, Minus, Multiply, Divide and IsGreatThan allocate %v times, want 0", allocs)
+	}
+}
+
+//legacyAmount is the implementation of Amount before the basic unit value is formatted on demand,
+//it is the baseline of benchmarks
+type legacyAmount struct {
+	curreny        Currency
+	basicUnitValue string
+	minorUnitValue int64
+}
+
+func (amount *legacyAmount) setMinorUnitValue(value int64) {
+	basicUnitFloatValue := float64(value) / math.Pow10(int(amount.curreny.MinorUnitDigits()))
+	formatStr := fmt.Sprintf("%%0.%df", amount.curreny.MinorUnitDigits())
+	stringValue := fmt.Sprintf(formatStr, basicUnitFloatValue)
+	amount.basicUnitValue = stringValue
+	stringValue = strings.Replace(stringValue, ".", "", 1)
+	intValue, _ := strconv.Atoi(stringValue)
+	amount.minorUnitValue = int64(intValue)
+}
+
+func (amount legacyAmount) Add(other legacyAmount) (legacyAmount, error) {
+	if amount.curreny.Code() != other.curreny.Code() {
+		return legacyAmount{}, fmt.Errorf("Amount add fail: curreny are not same")
+	}
+	result := legacyAmount{curreny: amount.curreny}
+	result.setMinorUnitValue(amount.minorUnitValue + other.minorUnitValue)
+	return result, nil
+}
+
+func (amount legacyAmount) String() string {
+	return fmt.Sprintf("%s %s", amount.curreny.Code(), amount.basicUnitValue)
+}
+
+func BenchmarkAdd(b *testing.B) {
+	usdAmount1, _ := Factory.NewAmountInBasicUnit("usd", "1.567")
+	usdAmount2, _ := Factory.NewAmountInBasicUnit("usd", "0.43")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount1, _ = usdAmount1.Add(usdAmount2)
+	}
+}
+
+func BenchmarkLegacyAdd(b *testing.B) {
+	currency, _ := Factory.GetCurrencyByCode("USD")
+	usdAmount1, usdAmount2 := legacyAmount{curreny: currency}, legacyAmount{curreny: currency}
+	usdAmount1.setMinorUnitValue(157)
+	usdAmount2.setMinorUnitValue(43)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount1, _ = usdAmount1.Add(usdAmount2)
+	}
+}
+
+func BenchmarkAddString(b *testing.B) {
+	usdAmount1, _ := Factory.NewAmountInBasicUnit("usd", "1.567")
+	usdAmount2, _ := Factory.NewAmountInBasicUnit("usd", "0.43")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount1, _ = usdAmount1.Add(usdAmount2)
+		_ = usdAmount1.String()
+	}
+}
+
+func BenchmarkLegacyAddString(b *testing.B) {
+	currency, _ := Factory.GetCurrencyByCode("USD")
+	usdAmount1, usdAmount2 := legacyAmount{curreny: currency}, legacyAmount{curreny: currency}
+	usdAmount1.setMinorUnitValue(157)
+	usdAmount2.setMinorUnitValue(43)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount1, _ = usdAmount1.Add(usdAmount2)
+		_ = usdAmount1.String()
+	}
+}
+
+func BenchmarkMinus(b *testing.B) {
+	usdAmount1, _ := Factory.NewAmountInBasicUnit("usd", "1.567")
+	usdAmount2, _ := Factory.NewAmountInBasicUnit("usd", "0.43")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount1, _ = usdAmount1.Minus(usdAmount2)
+	}
+}
+
+func BenchmarkMultiply(b *testing.B) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("usd", "1234.57")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount.Multiply(1.075)
+	}
+}
+
+func BenchmarkDivide(b *testing.B) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("usd", "1234.57")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount.Divide(3)
+	}
+}
+
+func BenchmarkFx(b *testing.B) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("usd", "1234.57")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount.Fx("CNY", 6.8)
+	}
+}
+
+func BenchmarkString(b *testing.B) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("usd", "-1234.57")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = usdAmount.String()
+	}
+}
+
+func BenchmarkMarshalJSON(b *testing.B) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("usd", "1234.57")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount.MarshalJSON()
+	}
+}
+
+func BenchmarkUnmarshalJSON(b *testing.B) {
+	data := []byte(`{"currency":"USD","value":"1234.57"}`)
+	var usdAmount Amount
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		usdAmount.UnmarshalJSON(data)
+	}
+}
@@ -0,0 +1,220 @@
+//Package amountcsv reads and writes CSV files with currency and amount columns row by row,
+//amounts are parsed by currency.Factory and written in normalized form (e.g.: usd,1.5 => USD,1.50)
+package amountcsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+//RowError is the error of a row, reading can continue after it
+type RowError struct {
+	Line   int    //the line number of row, starts from 1 and the header is line 1
+	Column string //the column name, empty if the error is not of a column
+	Err    error
+}
+
+//Error implements error
+func (err *RowError) Error() string {
+	if err.Column == "" {
+		return fmt.Sprintf("line %d: %v", err.Line, err.Err)
+	}
+	return fmt.Sprintf("line %d, column %s: %v", err.Line, err.Column, err.Err)
+}
+
+//Unwrap returns the underlying error
+func (err *RowError) Unwrap() error {
+	return err.Err
+}
+
+//Row is a row of CSV
+type Row struct {
+	Line   int             //the line number of row
+	Record []string        //the fields of row
+	Amount currency.Amount //the amount of currency and value columns
+}
+
+//Reader reads rows of CSV with a header line
+type Reader struct {
+	reader         *csv.Reader
+	header         []string
+	currencyColumn string
+	valueColumn    string
+	currencyIndex  int
+	valueIndex     int
+}
+
+//NewReader create a new reader and reads the header line,
+//column names are matched case-insensitively (e.g.: Currency, AMOUNT)
+//return error if the header can't be read or the columns are not found
+func NewReader(reader io.Reader, currencyColumn string, valueColumn string) (*Reader, error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+	header, err := csvReader.Read()
+	if err == io.EOF {
+		return nil, errors.New("header line is not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	header[0] = strings.TrimPrefix(header[0], "\ufeff") //UTF-8 BOM of spreadsheet exports
+
+	currencyIndex, err := columnIndex(header, currencyColumn)
+	if err != nil {
+		return nil, err
+	}
+	valueIndex, err := columnIndex(header, valueColumn)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{csvReader, header, header[currencyIndex], header[valueIndex], currencyIndex, valueIndex}, nil
+}
+
+//Header returns the header line
+func (reader *Reader) Header() []string {
+	return reader.header
+}
+
+//Read reads the next row, returns io.EOF at the end of CSV
+//return *RowError if the row has wrong number of fields or its amount is invalid, reading can continue
+//return other errors if CSV is malformed or can't be read, reading should stop
+func (reader *Reader) Read() (Row, error) {
+	record, err := reader.reader.Read()
+	if err == io.EOF {
+		return Row{}, err
+	}
+	if err != nil && !errors.Is(err, csv.ErrFieldCount) {
+		return Row{}, err
+	}
+	line, _ := reader.reader.FieldPos(0)
+	row := Row{Line: line, Record: record}
+	if err != nil {
+		return row, &RowError{line, "", fmt.Errorf("expect %d fields, got %d", len(reader.header), len(record))}
+	}
+	if _, err := currency.Factory.GetCurrencyByCode(record[reader.currencyIndex]); err != nil {
+		return row, &RowError{line, reader.currencyColumn, err}
+	}
+	row.Amount, err = currency.Factory.NewAmountInBasicUnit(record[reader.currencyIndex], record[reader.valueIndex])
+	if err != nil {
+		return row, &RowError{line, reader.valueColumn, err}
+	}
+	return row, nil
+}
+
+//Writer writes rows of CSV with normalized currency and value columns
+type Writer struct {
+	writer        *csv.Writer
+	currencyIndex int
+	valueIndex    int
+}
+
+//NewWriter create a new writer and writes the header line
+//return error if the columns are not found in header
+func NewWriter(writer io.Writer, header []string, currencyColumn string, valueColumn string) (*Writer, error) {
+	currencyIndex, err := columnIndex(header, currencyColumn)
+	if err != nil {
+		return nil, err
+	}
+	valueIndex, err := columnIndex(header, valueColumn)
+	if err != nil {
+		return nil, err
+	}
+	csvWriter := csv.NewWriter(writer)
+	if err := csvWriter.Write(header); err != nil {
+		return nil, err
+	}
+	return &Writer{csvWriter, currencyIndex, valueIndex}, nil
+}
+
+//Write writes the record of row, the currency and value columns are replaced by the amount of row
+func (writer *Writer) Write(row Row) error {
+	record := append([]string(nil), row.Record...)
+	record[writer.currencyIndex] = row.Amount.CurrencyCode()
+	record[writer.valueIndex] = row.Amount.BasicUnitValue()
+	return writer.writer.Write(record)
+}
+
+//Flush writes any buffered rows, returns the error of writing
+func (writer *Writer) Flush() error {
+	writer.writer.Flush()
+	return writer.writer.Error()
+}
+
+//Options is the options of Transform
+type Options struct {
+	CurrencyColumn string
+	ValueColumn    string
+	//Target is the currency code which amounts are converted to, empty means no conversion
+	Target string
+	//Rates is the rate source of conversion, required if Target is not empty
+	Rates currency.RateSource
+}
+
+//Transform reads rows of reader and writes normalized rows to writer,
+//if options.Target is not empty, the amounts are converted to Target in place and
+//the original amounts are appended as columns source_currency and source_value.
+//invalid rows are skipped and returned as row errors
+//return error if CSV is malformed or can't be read or written
+func Transform(reader io.Reader, writer io.Writer, options Options) ([]*RowError, error) {
+	if options.Target != "" && options.Rates == nil {
+		return nil, errors.New("rates is required by conversion")
+	}
+	csvReader, err := NewReader(reader, options.CurrencyColumn, options.ValueColumn)
+	if err != nil {
+		return nil, err
+	}
+	header := csvReader.Header()
+	if options.Target != "" {
+		header = append(append([]string(nil), header...), "source_currency", "source_value")
+	}
+	csvWriter, err := NewWriter(writer, header, options.CurrencyColumn, options.ValueColumn)
+	if err != nil {
+		return nil, err
+	}
+
+	var rowErrors []*RowError
+	for {
+		row, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		var rowErr *RowError
+		if errors.As(err, &rowErr) {
+			rowErrors = append(rowErrors, rowErr)
+			continue
+		}
+		if err != nil {
+			return rowErrors, err
+		}
+
+		if options.Target != "" {
+			converted, err := row.Amount.FxByRateSource(options.Target, options.Rates)
+			if err != nil {
+				rowErrors = append(rowErrors, &RowError{row.Line, "", err})
+				continue
+			}
+			row.Record = append(row.Record, row.Amount.CurrencyCode(), row.Amount.BasicUnitValue())
+			row.Amount = converted
+		}
+		if err := csvWriter.Write(row); err != nil {
+			return rowErrors, err
+		}
+	}
+	return rowErrors, csvWriter.Flush()
+}
+
+//columnIndex returns the index of column in header, matched case-insensitively
+//return error if the column is not found
+func columnIndex(header []string, column string) (int, error) {
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), strings.TrimSpace(column)) {
+			return i, nil
+		}
+	}
+	return -1, errors.New("column " + column + " is not found in header")
+}
@@ -0,0 +1,89 @@
+package amountcsv
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+func init() {
+	currency.Factory.NewCurrency("USD", 2)
+	currency.Factory.NewCurrency("CNY", 2)
+	currency.Factory.NewCurrency("JPY", 0)
+}
+
+func TestReader(t *testing.T) {
+	input := "\ufeffid,Currency,Amount\n1,usd,1.5\n2,ABC,1\n3,USD\n4,JPY,x\n5, JPY , 100 \n"
+	reader, err := NewReader(strings.NewReader(input), "currency", "amount")
+	if err != nil {
+		t.Fatalf("NewReader() == %v, want nil", err)
+	}
+
+	//case 1:
+	row, err := reader.Read()
+	if err != nil || row.Line != 2 || row.Amount.String() != "USD 1.50" {
+		t.Errorf("Read() == %d %s %v, want 2 USD 1.50 nil", row.Line, row.Amount.String(), err)
+	}
+
+	//case 2: row errors with line numbers, reading continues
+	wantLines := []int{3, 4, 5}
+	wantColumns := []string{"Currency", "", "Amount"}
+	for i := range wantLines {
+		_, err = reader.Read()
+		var rowErr *RowError
+		if !errors.As(err, &rowErr) || rowErr.Line != wantLines[i] || rowErr.Column != wantColumns[i] {
+			t.Errorf("Read() == %v, want row error of line %d column %q", err, wantLines[i], wantColumns[i])
+		}
+	}
+
+	//case 3:
+	row, err = reader.Read()
+	if err != nil || row.Amount.String() != "JPY 100" {
+		t.Errorf("Read() == %s %v, want JPY 100 nil", row.Amount.String(), err)
+	}
+	_, err = reader.Read()
+	if err != io.EOF {
+		t.Errorf("Read() at end == %v, want io.EOF", err)
+	}
+
+	//case 4:
+	_, err = NewReader(strings.NewReader("id,currency\n"), "currency", "amount")
+	if err == nil {
+		t.Errorf("NewReader() without amount column should be return an error, but no error return")
+	}
+}
+
+func TestTransform(t *testing.T) {
+	rates := currency.NewRateTable("")
+	rates.SetRate("USD", "CNY", 6.8)
+	input := "id,currency,amount,memo\n1,usd,1.5,\"a, b\"\n2,JPY,100,c\n3,CNY,2,d\n"
+
+	//case 1: normalize
+	var output bytes.Buffer
+	rowErrors, err := Transform(strings.NewReader(input), &output, Options{CurrencyColumn: "currency", ValueColumn: "amount"})
+	want := "id,currency,amount,memo\n1,USD,1.50,\"a, b\"\n2,JPY,100,c\n3,CNY,2.00,d\n"
+	if err != nil || len(rowErrors) != 0 || output.String() != want {
+		t.Errorf("Transform() == %q %v %v, want %q", output.String(), rowErrors, err, want)
+	}
+
+	//case 2: convert to CNY, JPY has no rate
+	output.Reset()
+	rowErrors, err = Transform(strings.NewReader(input), &output, Options{CurrencyColumn: "currency", ValueColumn: "amount", Target: "CNY", Rates: rates})
+	want = "id,currency,amount,memo,source_currency,source_value\n1,CNY,10.20,\"a, b\",USD,1.50\n3,CNY,2.00,d,CNY,2.00\n"
+	if err != nil || output.String() != want {
+		t.Errorf("Transform() to CNY == %q %v, want %q", output.String(), err, want)
+	}
+	if len(rowErrors) != 1 || rowErrors[0].Line != 3 {
+		t.Errorf("Transform() to CNY row errors == %v, want error of line 3", rowErrors)
+	}
+
+	//case 3:
+	_, err = Transform(strings.NewReader(input), &output, Options{CurrencyColumn: "currency", ValueColumn: "amount", Target: "CNY"})
+	if err == nil {
+		t.Errorf("Transform() to CNY without rates should be return an error, but no error return")
+	}
+}
@@ -0,0 +1,44 @@
+//Command currency-server serves the currency JSON API of package currencyhttp over HTTP.
+//It works fully offline with the built-in ISO 4217 currencies.
+//
+//Usage:
+//
+//	currency-server [--addr :8080] [--rates <file> [--pivot <code>]]
+//
+//The rates file is ECB XML (.xml) or CSV lines of from,to,rate, conversion is not available without it.
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+	"time"
+
+	currency "github.com/ciferliu/gocurrency"
+	"github.com/ciferliu/gocurrency/currencyhttp"
+)
+
+func main() {
+	addr := flag.String("addr", ":8080", "the address to listen on")
+	ratesFile := flag.String("rates", "", "the rates file, ECB XML (.xml) or CSV lines of from,to,rate")
+	pivot := flag.String("pivot", "", "the pivot currency of cross rates in rates file")
+	flag.Parse()
+
+	currency.Factory.InitFromBuiltinIso4217()
+	var rates currency.RateSource
+	if *ratesFile != "" {
+		table, err := currency.LoadRateFile(*ratesFile, *pivot)
+		if err != nil {
+			log.Fatalf("currency-server: %v", err)
+		}
+		rates = table
+	}
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           currencyhttp.NewHandler(rates),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	log.Printf("currency-server: listening on %s", *addr)
+	log.Fatal(server.ListenAndServe())
+}
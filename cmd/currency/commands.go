@@ -0,0 +1,237 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+
+	currency "github.com/ciferliu/gocurrency"
+	"github.com/ciferliu/gocurrency/amountcsv"
+)
+
+//initCurrencies registers the built-in ISO 4217 currencies
+func initCurrencies() {
+	currency.Factory.InitFromBuiltinIso4217()
+}
+
+//runList lists all currencies
+func runList(env *environment, args []string) (table, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return table{}, err
+	}
+	result := table{header: []string{"code", "minor_unit_digits"}}
+	for _, ccy := range currency.Factory.Currencies() {
+		result.rows = append(result.rows, []string{ccy.Code(), strconv.Itoa(int(ccy.MinorUnitDigits()))})
+	}
+	return result, nil
+}
+
+//runInfo shows a currency
+func runInfo(env *environment, args []string) (table, error) {
+	if err := expectArgs(args, 1); err != nil {
+		return table{}, err
+	}
+	ccy, err := currency.Factory.GetCurrencyByCode(args[0])
+	if err != nil {
+		return table{}, err
+	}
+	minorUnit, _ := currency.Factory.NewAmountInMinorUnit(ccy.Code(), 1)
+	return table{
+		header: []string{"code", "minor_unit_digits", "minor_unit"},
+		rows:   [][]string{{ccy.Code(), strconv.Itoa(int(ccy.MinorUnitDigits())), minorUnit.BasicUnitValue()}},
+	}, nil
+}
+
+//convertFlags defines the flags of convert
+func convertFlags(flags *flag.FlagSet, env *environment) {
+	flags.Float64Var(&env.rate, "rate", 0, "the rate of 1 <from> in <to>")
+	flags.StringVar(&env.ratesFile, "rates", "", "the rates file, ECB XML (.xml) or CSV lines of from,to,rate")
+	flags.StringVar(&env.pivot, "pivot", "", "the pivot currency of cross rates in rates file")
+}
+
+//runConvert converts an amount
+func runConvert(env *environment, args []string) (table, error) {
+	if err := expectArgs(args, 3); err != nil {
+		return table{}, err
+	}
+	amount, err := currency.Factory.NewAmountInBasicUnit(args[1], args[0])
+	if err != nil {
+		return table{}, err
+	}
+
+	var rate float64
+	var path []string
+	switch {
+	case env.rate != 0 && env.ratesFile != "":
+		return table{}, errors.New("--rate and --rates can't be used together")
+	case env.rate != 0:
+		rate = env.rate
+		path = []string{amount.CurrencyCode(), strings.ToUpper(args[2])}
+	case env.ratesFile != "":
+		rates, err := currency.LoadRateFile(env.ratesFile, env.pivot)
+		if err != nil {
+			return table{}, err
+		}
+		rate, path, err = rates.CrossRate(amount.CurrencyCode(), args[2])
+		if err != nil {
+			return table{}, err
+		}
+	default:
+		return table{}, errors.New("--rate or --rates is required")
+	}
+
+	result, err := amount.Fx(args[2], rate)
+	if err != nil {
+		return table{}, err
+	}
+	return table{
+		header: []string{"from", "to", "rate", "path"},
+		rows:   [][]string{{amount.String(), result.String(), strconv.FormatFloat(rate, 'f', -1, 64), strings.Join(path, ">")}},
+	}, nil
+}
+
+//formatFlags defines the flags of format
+func formatFlags(flags *flag.FlagSet, env *environment) {
+	flags.StringVar(&env.locale, "locale", "en-US", "the locale, one of "+strings.Join(currency.Locales(), ", "))
+}
+
+//runFormat formats an amount in locale
+func runFormat(env *environment, args []string) (table, error) {
+	if err := expectArgs(args, 2); err != nil {
+		return table{}, err
+	}
+	amount, err := currency.Factory.NewAmountInBasicUnit(args[1], args[0])
+	if err != nil {
+		return table{}, err
+	}
+	formatted, err := amount.Format(env.locale)
+	if err != nil {
+		return table{}, err
+	}
+	return table{header: []string{"amount", "locale", "formatted"}, rows: [][]string{{amount.String(), env.locale, formatted}}}, nil
+}
+
+//runSum sums "<code> <value>" lines of stdin per currency, empty lines are skipped
+func runSum(env *environment, args []string) (table, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return table{}, err
+	}
+
+	var amounts []currency.Amount
+	scanner := bufio.NewScanner(env.stdin)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		amount, err := currency.Factory.ParseAmount(scanner.Text())
+		if err != nil {
+			return table{}, fmt.Errorf("line %d: %v", line, err)
+		}
+		amounts = append(amounts, amount)
+	}
+	if err := scanner.Err(); err != nil {
+		return table{}, err
+	}
+
+	result := table{header: []string{"currency", "count", "sum"}}
+	groups := currency.GroupByCurrency(amounts)
+	for _, ccy := range currency.Factory.Currencies() {
+		group, exists := groups[ccy.Code()]
+		if !exists {
+			continue
+		}
+		sum, err := currency.Sum(group)
+		if err != nil {
+			return table{}, err
+		}
+		result.rows = append(result.rows, []string{ccy.Code(), strconv.Itoa(len(group)), sum.BasicUnitValue()})
+	}
+	return result, nil
+}
+
+//splitFlags defines the flags of split
+func splitFlags(flags *flag.FlagSet, env *environment) {
+	flags.StringVar(&env.ratios, "ratios", "", "split by comma separated ratios instead of n equal parts (e.g.: 70,20,10)")
+}
+
+//runSplit splits an amount into n equal parts or by ratios
+func runSplit(env *environment, args []string) (table, error) {
+	n := 3
+	if env.ratios != "" {
+		n = 2 //<n> is replaced by --ratios
+	}
+	if err := expectArgs(args, n); err != nil {
+		return table{}, err
+	}
+	amount, err := currency.Factory.NewAmountInBasicUnit(args[1], args[0])
+	if err != nil {
+		return table{}, err
+	}
+
+	var parts []currency.Amount
+	if env.ratios != "" {
+		var ratios []int64
+		for _, field := range strings.Split(env.ratios, ",") {
+			ratio, err := strconv.ParseInt(strings.TrimSpace(field), 10, 64)
+			if err != nil {
+				return table{}, fmt.Errorf("ratio %q is not an integer", field)
+			}
+			ratios = append(ratios, ratio)
+		}
+		parts, err = amount.Allocate(ratios...)
+	} else {
+		count, convErr := strconv.Atoi(args[2])
+		if convErr != nil {
+			return table{}, fmt.Errorf("n %q is not an integer", args[2])
+		}
+		parts, err = amount.Split(count)
+	}
+	if err != nil {
+		return table{}, err
+	}
+
+	result := table{header: []string{"part", "currency", "amount"}}
+	for i, part := range parts {
+		result.rows = append(result.rows, []string{strconv.Itoa(i + 1), part.CurrencyCode(), part.BasicUnitValue()})
+	}
+	return result, nil
+}
+
+//csvFlags defines the flags of csv
+func csvFlags(flags *flag.FlagSet, env *environment) {
+	flags.StringVar(&env.currencyColumn, "currency-column", "currency", "the name of currency column")
+	flags.StringVar(&env.valueColumn, "value-column", "amount", "the name of amount column")
+	flags.StringVar(&env.target, "to", "", "the currency which amounts are converted to")
+	flags.StringVar(&env.ratesFile, "rates", "", "the rates file, ECB XML (.xml) or CSV lines of from,to,rate")
+	flags.StringVar(&env.pivot, "pivot", "", "the pivot currency of cross rates in rates file")
+}
+
+//runCsv writes the normalized or converted CSV of stdin to stdout, invalid rows are skipped and reported to stderr
+func runCsv(env *environment, args []string) (table, error) {
+	if err := expectArgs(args, 0); err != nil {
+		return table{}, err
+	}
+	options := amountcsv.Options{CurrencyColumn: env.currencyColumn, ValueColumn: env.valueColumn, Target: env.target}
+	if env.ratesFile != "" {
+		rates, err := currency.LoadRateFile(env.ratesFile, env.pivot)
+		if err != nil {
+			return table{}, err
+		}
+		options.Rates = rates
+	}
+
+	rowErrors, err := amountcsv.Transform(env.stdin, env.stdout, options)
+	if err != nil {
+		return table{}, err
+	}
+	for _, rowErr := range rowErrors {
+		fmt.Fprintf(env.stderr, "currency csv: %v\n", rowErr)
+	}
+	if len(rowErrors) > 0 {
+		return table{}, fmt.Errorf("%d invalid rows are skipped", len(rowErrors))
+	}
+	return table{}, nil
+}
@@ -0,0 +1,184 @@
+//Command currency is a command-line tool for currency lookup, conversion, formatting, sum and split.
+//It works fully offline with the built-in ISO 4217 currencies.
+//
+//Usage:
+//
+//	currency <command> [arguments] [flags]
+//
+//The commands are:
+//
+//	list                               list currencies
+//	info <code>                        show a currency
+//	convert <value> <from> <to>        convert an amount by --rate or --rates file
+//	format <value> <code>              format an amount in --locale
+//	sum                                sum "<code> <value>" lines of stdin per currency
+//	split <value> <code> <n>           split an amount into n parts, or by --ratios
+//	csv                                normalize or convert the amounts of CSV in stdin
+//
+//All commands except csv accept --output text|json|csv.
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+//command is a sub-command of the tool
+type command struct {
+	usage string
+	//run runs the command with the positional arguments, flags are defined before parsing
+	run   func(env *environment, args []string) (table, error)
+	flags func(flags *flag.FlagSet, env *environment)
+}
+
+//environment is the input, output and flag values of a command
+type environment struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	output string
+
+	rate      float64
+	ratesFile string
+	pivot     string
+	locale    string
+	ratios    string
+
+	currencyColumn string
+	valueColumn    string
+	target         string
+}
+
+//table is the tabular result of a command, commands writing stdout directly return an empty table
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+var commands = map[string]command{
+	"list":    {"list", runList, nil},
+	"info":    {"info <code>", runInfo, nil},
+	"convert": {"convert <value> <from> <to> (--rate <rate> | --rates <file> [--pivot <code>])", runConvert, convertFlags},
+	"format":  {"format <value> <code> --locale <locale>", runFormat, formatFlags},
+	"sum":     {"sum < lines of \"<code> <value>\"", runSum, nil},
+	"split":   {"split <value> <code> (<n> | --ratios <r1,r2,...>)", runSplit, splitFlags},
+	"csv":     {"csv [--currency-column <name>] [--value-column <name>] [--to <code> --rates <file> [--pivot <code>]] < input.csv", runCsv, csvFlags},
+}
+
+//run runs the tool with arguments, returns the exit code
+func run(args []string, stdin io.Reader, stdout io.Writer, stderr io.Writer) int {
+	initCurrencies()
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(stderr)
+		return 2
+	}
+	cmd, exists := commands[args[0]]
+	if !exists {
+		fmt.Fprintf(stderr, "currency: unknown command %q\n", args[0])
+		printUsage(stderr)
+		return 2
+	}
+
+	env := &environment{stdin: stdin, stdout: stdout, stderr: stderr}
+	flags := flag.NewFlagSet(args[0], flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.StringVar(&env.output, "output", "text", "output format: text, json or csv")
+	if cmd.flags != nil {
+		cmd.flags(flags, env)
+	}
+	positionals, err := parseInterspersed(flags, args[1:])
+	if err != nil {
+		fmt.Fprintf(stderr, "usage: currency %s\n", cmd.usage)
+		return 2
+	}
+
+	result, err := cmd.run(env, positionals)
+	if err == nil && result.header != nil {
+		err = writeTable(stdout, env.output, result)
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "currency %s: %v\n", args[0], err)
+		return 1
+	}
+	return 0
+}
+
+//printUsage prints the usage of all commands
+func printUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage: currency <command> [arguments] [--output text|json|csv]")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(w, "  currency %s\n", commands[name].usage)
+	}
+}
+
+//parseInterspersed parse flags placed anywhere among positional arguments, returns the positional arguments
+func parseInterspersed(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positionals []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positionals, nil
+		}
+		positionals = append(positionals, args[0])
+		args = args[1:]
+	}
+}
+
+//writeTable writes table in format text, json or csv
+func writeTable(w io.Writer, format string, result table) error {
+	switch format {
+	case "text":
+		writer := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(writer, strings.ToUpper(strings.Join(result.header, "\t")))
+		for _, row := range result.rows {
+			fmt.Fprintln(writer, strings.Join(row, "\t"))
+		}
+		return writer.Flush()
+	case "csv":
+		writer := csv.NewWriter(w)
+		writer.Write(result.header)
+		writer.WriteAll(result.rows)
+		return writer.Error()
+	case "json":
+		objects := make([]map[string]string, len(result.rows))
+		for i, row := range result.rows {
+			objects[i] = make(map[string]string, len(row))
+			for j, value := range row {
+				objects[i][result.header[j]] = value
+			}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetEscapeHTML(false)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(objects)
+	}
+	return errors.New("output format " + format + " is not supported")
+}
+
+//expectArgs return error if the number of positional arguments is not n
+func expectArgs(args []string, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("expect %d arguments, got %d", n, len(args))
+	}
+	return nil
+}
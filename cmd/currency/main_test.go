@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func runCommand(stdin string, args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestInfo(t *testing.T) {
+	//case 1:
+	code, stdout, _ := runCommand("", "info", "kwd", "--output", "csv")
+	want := "code,minor_unit_digits,minor_unit\nKWD,3,0.001\n"
+	if code != 0 || stdout != want {
+		t.Errorf("currency info kwd --output csv == %d %q, want 0 %q", code, stdout, want)
+	}
+
+	//case 2:
+	code, _, stderr := runCommand("", "info", "ABC")
+	if code != 1 || stderr == "" {
+		t.Errorf("currency info ABC == %d %q, want 1 with error", code, stderr)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	//case 1:
+	code, stdout, _ := runCommand("", "convert", "100", "USD", "CNY", "--rate", "6.8", "--output", "csv")
+	want := "from,to,rate,path\nUSD 100.00,CNY 680.00,6.8,USD>CNY\n"
+	if code != 0 || stdout != want {
+		t.Errorf("currency convert 100 USD CNY --rate 6.8 == %d %q, want 0 %q", code, stdout, want)
+	}
+
+	//case 2:
+	rates := filepath.Join(t.TempDir(), "rates.csv")
+	os.WriteFile(rates, []byte("# from,to,rate\nUSD,CNY,8\nUSD,JPY,160\n"), 0644)
+	code, stdout, _ = runCommand("", "convert", "10", "CNY", "JPY", "--rates", rates, "--pivot", "USD", "--output", "json")
+	if code != 0 || !strings.Contains(stdout, `"to": "JPY 200"`) || !strings.Contains(stdout, `"path": "CNY>USD>JPY"`) {
+		t.Errorf("currency convert 10 CNY JPY --rates == %d %q, want JPY 200 through USD", code, stdout)
+	}
+
+	//case 3:
+	code, _, _ = runCommand("", "convert", "10", "CNY", "JPY")
+	if code != 1 {
+		t.Errorf("currency convert without rate == %d, want 1", code)
+	}
+}
+
+func TestFormatSumSplit(t *testing.T) {
+	//case 1:
+	code, stdout, _ := runCommand("", "format", "1234.5", "EUR", "--locale", "de-DE", "--output", "csv")
+	want := "amount,locale,formatted\nEUR 1234.50,de-DE,\"1.234,50 EUR\"\n"
+	if code != 0 || stdout != want {
+		t.Errorf("currency format 1234.5 EUR --locale de-DE == %d %q, want 0 %q", code, stdout, want)
+	}
+
+	//case 2:
+	code, stdout, _ = runCommand("USD 1.5\n\nCNY 2\nusd 0.25\n", "sum", "--output", "csv")
+	want = "currency,count,sum\nCNY,1,2.00\nUSD,2,1.75\n"
+	if code != 0 || stdout != want {
+		t.Errorf("currency sum == %d %q, want 0 %q", code, stdout, want)
+	}
+
+	//case 3:
+	code, _, stderr := runCommand("USD 1.5\nUSD x\n", "sum")
+	if code != 1 || !strings.Contains(stderr, "line 2") {
+		t.Errorf("currency sum with bad line == %d %q, want 1 with line 2", code, stderr)
+	}
+
+	//case 4:
+	code, stdout, _ = runCommand("", "split", "10", "USD", "--ratios", "1,1,1", "--output", "csv")
+	want = "part,currency,amount\n1,USD,3.34\n2,USD,3.33\n3,USD,3.33\n"
+	if code != 0 || stdout != want {
+		t.Errorf("currency split 10 USD --ratios 1,1,1 == %d %q, want 0 %q", code, stdout, want)
+	}
+
+	//case 5:
+	code, _, _ = runCommand("", "unknown")
+	if code != 2 {
+		t.Errorf("currency unknown == %d, want 2", code)
+	}
+}
+
+func TestCsv(t *testing.T) {
+	rates := filepath.Join(t.TempDir(), "rates.csv")
+	os.WriteFile(rates, []byte("USD,CNY,8\n"), 0644)
+
+	//case 1:
+	code, stdout, _ := runCommand("id,ccy,value\n1,usd,1.5\n", "csv", "--currency-column", "ccy", "--value-column", "value", "--to", "CNY", "--rates", rates)
+	want := "id,ccy,value,source_currency,source_value\n1,CNY,12.00,USD,1.50\n"
+	if code != 0 || stdout != want {
+		t.Errorf("currency csv --to CNY == %d %q, want 0 %q", code, stdout, want)
+	}
+
+	//case 2:
+	code, stdout, stderr := runCommand("currency,amount\nUSD,1\nUSD,x\n", "csv")
+	if code != 1 || stdout != "currency,amount\nUSD,1.00\n" || !strings.Contains(stderr, "line 3, column amount") {
+		t.Errorf("currency csv with bad row == %d %q %q, want 1 with error of line 3", code, stdout, stderr)
+	}
+}
@@ -0,0 +1,67 @@
+package currency
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+//RateQuote is an exchange rate with where it comes from and when it's effective
+type RateQuote struct {
+	Rate   float64   `json:"rate"`
+	Source string    `json:"source,omitempty"` //the name of rate provider (e.g.: ECB)
+	Time   time.Time `json:"time"`             //the effective time of rate
+}
+
+//Conversion is the audit record of a foreign exchange
+type Conversion struct {
+	Source       Amount       `json:"source"`
+	Target       Amount       `json:"target"`
+	Rate         RateQuote    `json:"rate"`
+	RoundingMode RoundingMode `json:"roundingMode"`
+	ExactValue   string       `json:"exactValue"` //the unrounded result in target currency's basic unit
+}
+
+//FxWithAudit foreign exchange by using the quoted rate and rounding mode,
+//returns the conversion record with the exact result before rounding
+//return error if targetCurrencyCode is not managed by factory
+//return error if mode is invalid
+//return error if rate is not greater than 0
+//return error if the target currency is the currency of amount and rate is not 1
+func (amount Amount) FxWithAudit(targetCurrencyCode string, quote RateQuote, mode RoundingMode) (Conversion, error) {
+	if err := mode.validate(); err != nil {
+		return Conversion{}, err
+	}
+	targetCurrencyCode = strings.ToUpper(strings.TrimSpace(targetCurrencyCode))
+	if targetCurrencyCode == amount.curreny.Code() && quote.Rate != 1 {
+		return Conversion{}, errors.New("fx rate of the same currency must be 1")
+	}
+	if !(quote.Rate > 0) {
+		return Conversion{}, errors.New("fx rate must be greater than 0")
+	}
+	targetCurrency, err := Factory.GetCurrencyByCode(targetCurrencyCode)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	rate, err := ratFromFloat(quote.Rate)
+	if err != nil {
+		return Conversion{}, err
+	}
+	exact := new(big.Rat).Mul(ratFromMinorUnit(amount.minorUnitValue, amount.curreny.MinorUnitDigits()), rate)
+	minorUnitValue, err := roundRatToMinorUnit(exact, targetCurrency.MinorUnitDigits(), mode)
+	if err != nil {
+		return Conversion{}, err
+	}
+
+	target := newZeroAmount(targetCurrency)
+	target.setMinorUnitValue(minorUnitValue)
+	return Conversion{
+		Source:       amount,
+		Target:       target,
+		Rate:         quote,
+		RoundingMode: mode,
+		ExactValue:   decimalString(exact, int(targetCurrency.MinorUnitDigits())),
+	}, nil
+}
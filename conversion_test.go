@@ -0,0 +1,68 @@
+package currency
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+}
+
+func TestFxWithAudit(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "2")
+	quote := RateQuote{Rate: 6.789, Source: "ECB", Time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)}
+
+	//case 1:
+	conversion, _ := usdAmount.FxWithAudit("CNY", quote, RoundHalfEven)
+	want, _ := Factory.NewAmountInBasicUnit("CNY", "13.58")
+	if !want.IsEquals(conversion.Target) || conversion.ExactValue != "13.578" {
+		t.Errorf("%s FxWithAudit(\"CNY\", 6.789, HALF_EVEN) == %s %s, want %s 13.578", usdAmount.String(), conversion.Target.String(), conversion.ExactValue, want.String())
+	}
+
+	//case 2:
+	conversion, _ = usdAmount.FxWithAudit("CNY", quote, RoundDown)
+	want, _ = Factory.NewAmountInBasicUnit("CNY", "13.57")
+	if !want.IsEquals(conversion.Target) {
+		t.Errorf("%s FxWithAudit(\"CNY\", 6.789, DOWN) == %s, want %s", usdAmount.String(), conversion.Target.String(), want.String())
+	}
+
+	//case 3:
+	data, _ := json.Marshal(conversion)
+	got := string(data)
+	for _, want := range []string{`"source":{"currency":"USD","value":"2.00"}`, `"target":{"currency":"CNY","value":"13.57"}`, `"source":"ECB"`, `"roundingMode":"DOWN"`, `"exactValue":"13.578"`} {
+		if !strings.Contains(got, want) {
+			t.Errorf("json.Marshal(conversion) == %s, want contains %s", got, want)
+		}
+	}
+
+	//case 4:
+	var decoded Conversion
+	err := json.Unmarshal(data, &decoded)
+	if err != nil || !decoded.Target.IsEquals(conversion.Target) || decoded.RoundingMode != RoundDown {
+		t.Errorf("json.Unmarshal(%s) == %v %v, want %v", got, decoded, err, conversion)
+	}
+	err = json.Unmarshal([]byte(`{"target":null}`), &decoded)
+	if err != nil || !decoded.Target.IsEquals(conversion.Target) {
+		t.Errorf("json.Unmarshal({\"target\":null}) == %s %v, want %s unchanged", decoded.Target.String(), err, conversion.Target.String())
+	}
+
+	//case 5:
+	_, err = usdAmount.FxWithAudit("CNY", RateQuote{}, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s FxWithAudit(\"CNY\", 0) should be return an error, but no error return", usdAmount.String())
+	}
+
+	//case 6:
+	conversion, err = usdAmount.FxWithAudit("usd", RateQuote{Rate: 1, Source: "ECB"}, RoundHalfEven)
+	if err != nil || !conversion.Target.IsEquals(usdAmount) || conversion.Rate.Rate != 1 {
+		t.Errorf("%s FxWithAudit(\"usd\", 1) == %s %v %v, want %s rate 1", usdAmount.String(), conversion.Target.String(), conversion.Rate.Rate, err, usdAmount.String())
+	}
+	_, err = usdAmount.FxWithAudit("USD", quote, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s FxWithAudit(\"USD\", 6.789) should be return an error, but no error return", usdAmount.String())
+	}
+}
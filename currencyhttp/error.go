@@ -0,0 +1,61 @@
+package currencyhttp
+
+import (
+	"errors"
+	"net/http"
+)
+
+//ErrorType is the type of error response, clients should switch on it instead of the message
+type ErrorType string
+
+const (
+	//ErrInvalidRequest means the request body or parameters are malformed
+	ErrInvalidRequest ErrorType = "invalid_request"
+	//ErrInvalidCurrency means the currency code is not a three-letter alphabetic code
+	ErrInvalidCurrency ErrorType = "invalid_currency"
+	//ErrCurrencyNotFound means the currency code is not managed by currency.Factory
+	ErrCurrencyNotFound ErrorType = "currency_not_found"
+	//ErrInvalidAmount means the value of amount is not a numberic value
+	ErrInvalidAmount ErrorType = "invalid_amount"
+	//ErrCurrencyMismatch means the currencies of amounts are not same
+	ErrCurrencyMismatch ErrorType = "currency_mismatch"
+	//ErrInvalidOperation means the operation can't be applied to the amounts (e.g.: divide by 0)
+	ErrInvalidOperation ErrorType = "invalid_operation"
+	//ErrRateNotFound means the rate source can't provide the rate
+	ErrRateNotFound ErrorType = "rate_not_found"
+	//ErrNotFound means the path is not found
+	ErrNotFound ErrorType = "not_found"
+	//ErrInternal means an unexpected error
+	ErrInternal ErrorType = "internal"
+)
+
+//Error is the error of API, written as {"error":{"type":"...","message":"..."}} with status
+type Error struct {
+	Status  int       `json:"-"`
+	Type    ErrorType `json:"type"`
+	Message string    `json:"message"`
+}
+
+//newError create a new API error
+func newError(status int, errorType ErrorType, message string) *Error {
+	return &Error{status, errorType, message}
+}
+
+//Error implements error
+func (err *Error) Error() string {
+	return string(err.Type) + ": " + err.Message
+}
+
+//errorResponse is the JSON format of error response
+type errorResponse struct {
+	Error *Error `json:"error"`
+}
+
+//writeError writes err as JSON response, err which is not *Error is written as ErrInternal
+func writeError(w http.ResponseWriter, err error) {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		apiErr = newError(http.StatusInternalServerError, ErrInternal, err.Error())
+	}
+	writeJSON(w, apiErr.Status, errorResponse{apiErr})
+}
@@ -0,0 +1,332 @@
+//Package currencyhttp is an embeddable http.Handler exposing currency lookup, amount normalization,
+//arithmetic, allocation and conversion as a JSON API, so that non-Go services share the same rounding rules
+//
+//Endpoints:
+//
+//	GET  /currencies              list currencies
+//	GET  /currencies/{code}       look up a currency
+//	POST /amounts/normalize       normalize and validate an amount
+//	POST /amounts/arithmetic      add, subtract, multiply or divide amounts
+//	POST /amounts/allocate        split an amount into n parts or by ratios, at most 1000
+//	POST /amounts/convert         convert an amount by the configured rate source
+//
+//Amounts are JSON objects of currency code and decimal string (e.g.: {"currency":"USD","value":"1.00"}),
+//errors are JSON objects of type and message (e.g.: {"error":{"type":"currency_not_found","message":"..."}})
+package currencyhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+//maxBodyBytes is the max size of request body
+const maxBodyBytes = 1 << 20
+
+//maxParts is the max number of parts or ratios of allocate, so that a request can't exhaust the memory of server
+const maxParts = 1000
+
+//Handler is the http.Handler of currency JSON API
+type Handler struct {
+	rates currency.RateSource
+	mux   *http.ServeMux
+}
+
+//NewHandler create a new handler, the currencies are managed by currency.Factory
+//rates is the rate source of conversion, nil means conversion is not available
+func NewHandler(rates currency.RateSource) *Handler {
+	handler := &Handler{rates: rates, mux: http.NewServeMux()}
+	handler.mux.HandleFunc("GET /currencies", handler.listCurrencies)
+	handler.mux.HandleFunc("GET /currencies/{code}", handler.getCurrency)
+	handler.mux.HandleFunc("POST /amounts/normalize", handler.normalize)
+	handler.mux.HandleFunc("POST /amounts/arithmetic", handler.arithmetic)
+	handler.mux.HandleFunc("POST /amounts/allocate", handler.allocate)
+	handler.mux.HandleFunc("POST /amounts/convert", handler.convert)
+	handler.mux.HandleFunc("/", handler.notFound)
+	return handler
+}
+
+//ServeHTTP implements http.Handler
+func (handler *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	handler.mux.ServeHTTP(w, r)
+}
+
+//currencyResponse is the JSON format of currency
+type currencyResponse struct {
+	Code            string `json:"code"`
+	MinorUnitDigits uint8  `json:"minorUnitDigits"`
+}
+
+//amountRequest is the JSON format of amount in request, the value is kept as text for validation
+type amountRequest struct {
+	Currency string `json:"currency"`
+	Value    string `json:"value"`
+}
+
+//normalizeResponse is the response of normalize
+type normalizeResponse struct {
+	Amount         currency.Amount `json:"amount"`
+	MinorUnitValue int64           `json:"minorUnitValue"`
+}
+
+//arithmeticRequest is the request of arithmetic,
+//right is the other amount of add and subtract, factor is the factor of multiply and divide
+type arithmeticRequest struct {
+	Operation string         `json:"operation"` //add, subtract, multiply or divide
+	Left      amountRequest  `json:"left"`
+	Right     *amountRequest `json:"right"`
+	Factor    *float64       `json:"factor"`
+}
+
+//allocateRequest is the request of allocate, either parts or ratios is required, at most maxParts
+type allocateRequest struct {
+	Amount amountRequest `json:"amount"`
+	Parts  int           `json:"parts"`
+	Ratios []int64       `json:"ratios"`
+}
+
+//convertRequest is the request of convert
+type convertRequest struct {
+	Amount amountRequest `json:"amount"`
+	To     string        `json:"to"`
+}
+
+//convertResponse is the response of convert
+type convertResponse struct {
+	Amount    currency.Amount `json:"amount"`
+	Converted currency.Amount `json:"converted"`
+	Rate      float64         `json:"rate"`
+}
+
+//listCurrencies handles GET /currencies
+func (handler *Handler) listCurrencies(w http.ResponseWriter, r *http.Request) {
+	currencies := currency.Factory.Currencies()
+	response := make([]currencyResponse, len(currencies))
+	for i, ccy := range currencies {
+		response[i] = currencyResponse{ccy.Code(), ccy.MinorUnitDigits()}
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+//getCurrency handles GET /currencies/{code}
+func (handler *Handler) getCurrency(w http.ResponseWriter, r *http.Request) {
+	ccy, err := lookupCurrency(r.PathValue("code"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, currencyResponse{ccy.Code(), ccy.MinorUnitDigits()})
+}
+
+//normalize handles POST /amounts/normalize
+func (handler *Handler) normalize(w http.ResponseWriter, r *http.Request) {
+	var request amountRequest
+	if err := decodeRequest(w, r, &request); err != nil {
+		writeError(w, err)
+		return
+	}
+	amount, err := newAmount(request)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, normalizeResponse{amount, amount.MinorUnitValue()})
+}
+
+//arithmetic handles POST /amounts/arithmetic
+func (handler *Handler) arithmetic(w http.ResponseWriter, r *http.Request) {
+	var request arithmeticRequest
+	if err := decodeRequest(w, r, &request); err != nil {
+		writeError(w, err)
+		return
+	}
+	left, err := newAmount(request.Left)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var result currency.Amount
+	switch strings.ToLower(request.Operation) {
+	case "add", "subtract":
+		if request.Right == nil {
+			writeError(w, newError(http.StatusBadRequest, ErrInvalidRequest, "right is required by "+request.Operation))
+			return
+		}
+		right, err := newAmount(*request.Right)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		if left.CurrencyCode() != right.CurrencyCode() {
+			writeError(w, newError(http.StatusUnprocessableEntity, ErrCurrencyMismatch, "currencies of left and right are not same"))
+			return
+		}
+		if strings.ToLower(request.Operation) == "add" {
+			result, err = left.Add(right)
+		} else {
+			result, err = left.Minus(right)
+		}
+		if err != nil {
+			writeError(w, newError(http.StatusUnprocessableEntity, ErrInvalidOperation, err.Error()))
+			return
+		}
+	case "multiply", "divide":
+		if request.Factor == nil {
+			writeError(w, newError(http.StatusBadRequest, ErrInvalidRequest, "factor is required by "+request.Operation))
+			return
+		}
+		if strings.ToLower(request.Operation) == "multiply" {
+			result, err = left.MultiplyChecked(*request.Factor)
+		} else {
+			result, err = left.Divide(*request.Factor)
+		}
+		if err != nil {
+			writeError(w, newError(http.StatusUnprocessableEntity, ErrInvalidOperation, err.Error()))
+			return
+		}
+	default:
+		writeError(w, newError(http.StatusBadRequest, ErrInvalidRequest, "operation must be one of add, subtract, multiply, divide"))
+		return
+	}
+	writeJSON(w, http.StatusOK, normalizeResponse{result, result.MinorUnitValue()})
+}
+
+//allocate handles POST /amounts/allocate
+func (handler *Handler) allocate(w http.ResponseWriter, r *http.Request) {
+	var request allocateRequest
+	if err := decodeRequest(w, r, &request); err != nil {
+		writeError(w, err)
+		return
+	}
+	if (request.Parts == 0) == (len(request.Ratios) == 0) {
+		writeError(w, newError(http.StatusBadRequest, ErrInvalidRequest, "either parts or ratios is required"))
+		return
+	}
+	if request.Parts > maxParts || len(request.Ratios) > maxParts {
+		writeError(w, newError(http.StatusBadRequest, ErrInvalidRequest, "parts or ratios can't be more than "+strconv.Itoa(maxParts)))
+		return
+	}
+	amount, err := newAmount(request.Amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	var parts []currency.Amount
+	if request.Parts != 0 {
+		parts, err = amount.Split(request.Parts)
+	} else {
+		parts, err = amount.Allocate(request.Ratios...)
+	}
+	if err != nil {
+		writeError(w, newError(http.StatusUnprocessableEntity, ErrInvalidOperation, err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string][]currency.Amount{"parts": parts})
+}
+
+//convert handles POST /amounts/convert
+func (handler *Handler) convert(w http.ResponseWriter, r *http.Request) {
+	if handler.rates == nil {
+		writeError(w, newError(http.StatusServiceUnavailable, ErrRateNotFound, "no rate source is configured"))
+		return
+	}
+	var request convertRequest
+	if err := decodeRequest(w, r, &request); err != nil {
+		writeError(w, err)
+		return
+	}
+	amount, err := newAmount(request.Amount)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	target, err := lookupCurrency(request.To)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	rate := 1.0
+	if target.Code() != amount.CurrencyCode() {
+		rate, err = handler.rates.Rate(amount.CurrencyCode(), target.Code())
+		if err != nil {
+			writeError(w, newError(http.StatusUnprocessableEntity, ErrRateNotFound, err.Error()))
+			return
+		}
+	}
+	converted, err := amount.Fx(target.Code(), rate)
+	if err != nil {
+		writeError(w, newError(http.StatusUnprocessableEntity, ErrInvalidOperation, err.Error()))
+		return
+	}
+	writeJSON(w, http.StatusOK, convertResponse{amount, converted, rate})
+}
+
+//notFound handles the paths which are not found
+func (handler *Handler) notFound(w http.ResponseWriter, r *http.Request) {
+	writeError(w, newError(http.StatusNotFound, ErrNotFound, r.Method+" "+r.URL.Path+" is not found"))
+}
+
+//lookupCurrency returns the currency of code
+//return ErrInvalidCurrency error if code is not a three-letter alphabetic code
+//return ErrCurrencyNotFound error if code is not managed by currency.Factory
+func lookupCurrency(code string) (currency.Currency, error) {
+	ccy, err := currency.Factory.GetCurrencyByCode(code)
+	if err == nil {
+		return ccy, nil
+	}
+	if !isAlphabeticCode(strings.TrimSpace(code)) {
+		return currency.Currency{}, newError(http.StatusBadRequest, ErrInvalidCurrency, err.Error())
+	}
+	return currency.Currency{}, newError(http.StatusNotFound, ErrCurrencyNotFound, err.Error())
+}
+
+//isAlphabeticCode return true if code is a three-letter alphabetic code in any case
+func isAlphabeticCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range strings.ToUpper(code) {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+//newAmount create an amount of request
+//return ErrInvalidAmount error if the value is not a numberic value
+func newAmount(request amountRequest) (currency.Amount, error) {
+	ccy, err := lookupCurrency(request.Currency)
+	if err != nil {
+		return currency.Amount{}, err
+	}
+	amount, err := currency.Factory.NewAmountInBasicUnit(ccy.Code(), request.Value)
+	if err != nil {
+		return currency.Amount{}, newError(http.StatusBadRequest, ErrInvalidAmount, err.Error())
+	}
+	return amount, nil
+}
+
+//decodeRequest decode the JSON body of request, unknown fields are rejected
+//return ErrInvalidRequest error if the body is not valid JSON of value, or is larger than maxBodyBytes
+func decodeRequest(w http.ResponseWriter, r *http.Request, value interface{}) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(value); err != nil {
+		return newError(http.StatusBadRequest, ErrInvalidRequest, "request body is invalid: "+err.Error())
+	}
+	return nil
+}
+
+//writeJSON writes value as JSON response with status
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value)
+}
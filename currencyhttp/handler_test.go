@@ -0,0 +1,182 @@
+package currencyhttp
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+func init() {
+	currency.Factory.NewCurrency("USD", 2)
+	currency.Factory.NewCurrency("CNY", 2)
+}
+
+func newTestHandler() *Handler {
+	rates := currency.NewRateTable("")
+	rates.SetRate("USD", "CNY", 6.8)
+	return NewHandler(rates)
+}
+
+//serve serves the request by handler, returns the status and body
+func serve(handler http.Handler, method string, path string, body string) (int, string) {
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, path, strings.NewReader(body)))
+	return recorder.Code, recorder.Body.String()
+}
+
+//errorType returns the type of error response body
+func errorType(body string) ErrorType {
+	var response struct {
+		Error Error `json:"error"`
+	}
+	json.Unmarshal([]byte(body), &response)
+	return response.Error.Type
+}
+
+func TestGetCurrency(t *testing.T) {
+	handler := newTestHandler()
+
+	//case 1:
+	status, body := serve(handler, "GET", "/currencies/usd", "")
+	if status != http.StatusOK || body != "{\"code\":\"USD\",\"minorUnitDigits\":2}\n" {
+		t.Errorf("GET /currencies/usd == %d %s, want 200 USD", status, body)
+	}
+
+	//case 2:
+	status, body = serve(handler, "GET", "/currencies/ABC", "")
+	if status != http.StatusNotFound || errorType(body) != ErrCurrencyNotFound {
+		t.Errorf("GET /currencies/ABC == %d %s, want 404 %s", status, body, ErrCurrencyNotFound)
+	}
+
+	//case 3:
+	status, body = serve(handler, "GET", "/currencies/U1D", "")
+	if status != http.StatusBadRequest || errorType(body) != ErrInvalidCurrency {
+		t.Errorf("GET /currencies/U1D == %d %s, want 400 %s", status, body, ErrInvalidCurrency)
+	}
+
+	//case 4:
+	status, body = serve(handler, "GET", "/unknown", "")
+	if status != http.StatusNotFound || errorType(body) != ErrNotFound {
+		t.Errorf("GET /unknown == %d %s, want 404 %s", status, body, ErrNotFound)
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	handler := newTestHandler()
+
+	//case 1:
+	status, body := serve(handler, "POST", "/amounts/normalize", `{"currency":"usd","value":" 1.005 "}`)
+	want := "{\"amount\":{\"currency\":\"USD\",\"value\":\"1.00\"},\"minorUnitValue\":100}\n"
+	if status != http.StatusOK || body != want {
+		t.Errorf("POST /amounts/normalize == %d %s, want 200 %s", status, body, want)
+	}
+
+	//case 2:
+	status, body = serve(handler, "POST", "/amounts/normalize", `{"currency":"USD","value":"abc"}`)
+	if status != http.StatusBadRequest || errorType(body) != ErrInvalidAmount {
+		t.Errorf("POST /amounts/normalize abc == %d %s, want 400 %s", status, body, ErrInvalidAmount)
+	}
+
+	//case 3:
+	status, body = serve(handler, "POST", "/amounts/normalize", `{"currency":"USD","amount":"1"}`)
+	if status != http.StatusBadRequest || errorType(body) != ErrInvalidRequest {
+		t.Errorf("POST /amounts/normalize with unknown field == %d %s, want 400 %s", status, body, ErrInvalidRequest)
+	}
+
+	//case 4: body larger than maxBodyBytes
+	status, body = serve(handler, "POST", "/amounts/normalize", `{"currency":"USD","value":"`+strings.Repeat("1", maxBodyBytes)+`"}`)
+	if status != http.StatusBadRequest || errorType(body) != ErrInvalidRequest {
+		t.Errorf("POST /amounts/normalize with large body == %d %.100s, want 400 %s", status, body, ErrInvalidRequest)
+	}
+}
+
+func TestArithmetic(t *testing.T) {
+	handler := newTestHandler()
+
+	//case 1:
+	status, body := serve(handler, "POST", "/amounts/arithmetic",
+		`{"operation":"subtract","left":{"currency":"USD","value":"1.50"},"right":{"currency":"USD","value":"2"}}`)
+	if status != http.StatusOK || !strings.Contains(body, `"value":"-0.50"`) {
+		t.Errorf("POST /amounts/arithmetic subtract == %d %s, want 200 USD -0.50", status, body)
+	}
+
+	//case 2:
+	status, body = serve(handler, "POST", "/amounts/arithmetic", `{"operation":"multiply","left":{"currency":"USD","value":"10"},"factor":0.125}`)
+	if status != http.StatusOK || !strings.Contains(body, `"value":"1.25"`) {
+		t.Errorf("POST /amounts/arithmetic multiply == %d %s, want 200 USD 1.25", status, body)
+	}
+
+	//case 3:
+	status, body = serve(handler, "POST", "/amounts/arithmetic",
+		`{"operation":"add","left":{"currency":"USD","value":"1"},"right":{"currency":"CNY","value":"1"}}`)
+	if status != http.StatusUnprocessableEntity || errorType(body) != ErrCurrencyMismatch {
+		t.Errorf("POST /amounts/arithmetic USD+CNY == %d %s, want 422 %s", status, body, ErrCurrencyMismatch)
+	}
+
+	//case 4:
+	status, body = serve(handler, "POST", "/amounts/arithmetic", `{"operation":"divide","left":{"currency":"USD","value":"1"},"factor":0}`)
+	if status != http.StatusUnprocessableEntity || errorType(body) != ErrInvalidOperation {
+		t.Errorf("POST /amounts/arithmetic divide by 0 == %d %s, want 422 %s", status, body, ErrInvalidOperation)
+	}
+
+	//case 5:
+	status, body = serve(handler, "POST", "/amounts/arithmetic", `{"operation":"multiply","left":{"currency":"USD","value":"1"},"factor":1e300}`)
+	if status != http.StatusUnprocessableEntity || errorType(body) != ErrInvalidOperation {
+		t.Errorf("POST /amounts/arithmetic multiply by 1e300 == %d %s, want 422 %s", status, body, ErrInvalidOperation)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	handler := newTestHandler()
+
+	//case 1:
+	status, body := serve(handler, "POST", "/amounts/allocate", `{"amount":{"currency":"USD","value":"10"},"parts":3}`)
+	want := "{\"parts\":[{\"currency\":\"USD\",\"value\":\"3.34\"},{\"currency\":\"USD\",\"value\":\"3.33\"},{\"currency\":\"USD\",\"value\":\"3.33\"}]}\n"
+	if status != http.StatusOK || body != want {
+		t.Errorf("POST /amounts/allocate parts == %d %s, want 200 %s", status, body, want)
+	}
+
+	//case 2:
+	status, body = serve(handler, "POST", "/amounts/allocate", `{"amount":{"currency":"USD","value":"10"},"parts":3,"ratios":[1,1]}`)
+	if status != http.StatusBadRequest || errorType(body) != ErrInvalidRequest {
+		t.Errorf("POST /amounts/allocate with parts and ratios == %d %s, want 400 %s", status, body, ErrInvalidRequest)
+	}
+
+	//case 3:
+	status, body = serve(handler, "POST", "/amounts/allocate", `{"amount":{"currency":"USD","value":"10"},"parts":2000000000}`)
+	if status != http.StatusBadRequest || errorType(body) != ErrInvalidRequest {
+		t.Errorf("POST /amounts/allocate with 2000000000 parts == %d %s, want 400 %s", status, body, ErrInvalidRequest)
+	}
+	ratios := strings.Repeat("1,", maxParts) + "1"
+	status, body = serve(handler, "POST", "/amounts/allocate", `{"amount":{"currency":"USD","value":"10"},"ratios":[`+ratios+`]}`)
+	if status != http.StatusBadRequest || errorType(body) != ErrInvalidRequest {
+		t.Errorf("POST /amounts/allocate with %d ratios == %d %s, want 400 %s", maxParts+1, status, body, ErrInvalidRequest)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	handler := newTestHandler()
+
+	//case 1:
+	status, body := serve(handler, "POST", "/amounts/convert", `{"amount":{"currency":"USD","value":"10"},"to":"CNY"}`)
+	if status != http.StatusOK || !strings.Contains(body, `"converted":{"currency":"CNY","value":"68.00"}`) {
+		t.Errorf("POST /amounts/convert == %d %s, want 200 CNY 68.00", status, body)
+	}
+
+	//case 2:
+	currency.Factory.NewCurrency("EUR", 2)
+	status, body = serve(handler, "POST", "/amounts/convert", `{"amount":{"currency":"USD","value":"10"},"to":"EUR"}`)
+	if status != http.StatusUnprocessableEntity || errorType(body) != ErrRateNotFound {
+		t.Errorf("POST /amounts/convert to EUR == %d %s, want 422 %s", status, body, ErrRateNotFound)
+	}
+
+	//case 3:
+	status, body = serve(NewHandler(nil), "POST", "/amounts/convert", `{"amount":{"currency":"USD","value":"10"},"to":"CNY"}`)
+	if status != http.StatusServiceUnavailable || errorType(body) != ErrRateNotFound {
+		t.Errorf("POST /amounts/convert without rates == %d %s, want 503 %s", status, body, ErrRateNotFound)
+	}
+}
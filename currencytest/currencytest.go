@@ -0,0 +1,42 @@
+//Package currencytest provides helpers for tests of code using package currency:
+//registries pre-seeded with ISO 4217 data (Isolate replaces currency.Factory for a test), fixed rate sources,
+//Must helpers which fail the test instead of returning errors, and comparisons with readable diffs
+package currencytest
+
+import (
+	"testing"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+//Registry is a currency registry, implemented by *currency.CurrencyFactory (e.g.: &currency.Factory, currency.NewFactory())
+type Registry interface {
+	NewCurrency(currencyCode string, minorUnitDigits uint8) (currency.Currency, error)
+	GetCurrencyByCode(currencyCode string) (currency.Currency, error)
+	Currencies() []currency.Currency
+	NewAmountInBasicUnit(currencyCode string, basicUnitValue string) (currency.Amount, error)
+	NewAmountInMinorUnit(currencyCode string, minorUnitValue int64) (currency.Amount, error)
+	ParseAmount(text string) (currency.Amount, error)
+}
+
+//NewRegistry returns a new registry pre-seeded with the built-in ISO 4217 currencies,
+//the currencies registered in it are not visible to currency.Factory, create its amounts by MustAmountIn.
+//It's not isolated: its amounts use currency.Factory for Fx and JSON decoding, use Isolate for that
+func NewRegistry() Registry {
+	registry := currency.NewFactory()
+	registry.InitFromBuiltinIso4217()
+	return registry
+}
+
+//Isolate replaces currency.Factory by a new registry pre-seeded with the built-in ISO 4217 currencies,
+//and restores it when the test and all its subtests complete, so that the currencies registered by the test
+//don't leak to other tests. Like t.Setenv, it can't be used in parallel tests
+func Isolate(t testing.TB) Registry {
+	t.Helper()
+	saved := currency.Factory
+	registry := currency.NewFactory()
+	registry.InitFromBuiltinIso4217()
+	currency.Factory = *registry
+	t.Cleanup(func() { currency.Factory = saved })
+	return &currency.Factory
+}
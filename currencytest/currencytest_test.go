@@ -0,0 +1,82 @@
+package currencytest
+
+import (
+	"errors"
+	"testing"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+func TestIsolate(t *testing.T) {
+	//case 1:
+	t.Run("isolated", func(t *testing.T) {
+		registry := Isolate(t)
+		registry.NewCurrency("ZZZ", 1)
+		AssertAmount(t, MustAmount(t, "zzz 1.25"), MustAmount(t, "ZZZ 1.2"))
+		if _, err := registry.GetCurrencyByCode("KWD"); err != nil {
+			t.Errorf("Isolate().GetCurrencyByCode(\"KWD\") == %v, want nil", err)
+		}
+	})
+	if _, err := currency.Factory.GetCurrencyByCode("ZZZ"); err == nil {
+		t.Errorf("currency.Factory.GetCurrencyByCode(\"ZZZ\") registered in Isolate() should be return an error, but no error return")
+	}
+
+	//case 2:
+	registry := NewRegistry()
+	registry.NewCurrency("ZZY", 0)
+	if _, err := currency.Factory.GetCurrencyByCode("ZZY"); err == nil {
+		t.Errorf("currency.Factory.GetCurrencyByCode(\"ZZY\") registered in NewRegistry() should be return an error, but no error return")
+	}
+	if amount := MustAmountIn(t, registry, "zzy 1.5"); amount.String() != "ZZY 2" {
+		t.Errorf("MustAmountIn(NewRegistry(), \"zzy 1.5\") == %s, want ZZY 2", amount.String())
+	}
+}
+
+func TestRates(t *testing.T) {
+	Isolate(t)
+
+	//case 1:
+	rates := FixedRates{"USD/CNY": 8}
+	rate, err := rates.Rate("cny", "USD")
+	if err != nil || rate != 0.125 {
+		t.Errorf("FixedRates.Rate(\"cny\", \"USD\") == %v %v, want 0.125", rate, err)
+	}
+	_, err = rates.Rate("USD", "JPY")
+	if err == nil {
+		t.Errorf("FixedRates.Rate(\"USD\", \"JPY\") should be return an error, but no error return")
+	}
+
+	//case 2:
+	converted, _ := MustAmount(t, "USD 10").FxByRateSource("CNY", ConstantRate(7))
+	AssertAmount(t, converted, MustAmount(t, "CNY 70"))
+
+	//case 3:
+	want := errors.New("rate service is down")
+	_, err = MustAmount(t, "USD 10").FxByRateSource("CNY", FailingRates{want})
+	if err != want {
+		t.Errorf("FxByRateSource(FailingRates) == %v, want %v", err, want)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	Isolate(t)
+
+	//case 1:
+	got := DiffAmounts(MustAmounts(t, "USD 3.33", "USD 3.33", "CNY 1"), MustAmounts(t, "USD 3.34", "USD 3.33", "USD 1", "USD 2"))
+	want := "[0] got USD 3.33, want USD 3.34 (off by USD -0.01)\n[2] got CNY 1.00, want USD 1.00 (currencies differ)\n[3] got nothing, want USD 2.00"
+	if got != want {
+		t.Errorf("DiffAmounts() == %q, want %q", got, want)
+	}
+
+	//case 2:
+	got = DiffWallets(MustWallet(t, "USD 1", "CNY 2"), MustWallet(t, "USD 1.5", "USD 0.5", "EUR 3"))
+	want = "CNY: got 2.00, want nothing\nEUR: got nothing, want 3.00\nUSD: got 1.00, want 2.00 (off by -1.00)"
+	if got != want {
+		t.Errorf("DiffWallets() == %q, want %q", got, want)
+	}
+
+	//case 3:
+	if diff := DiffWallets(nil, MustWallet(t)); diff != "" {
+		t.Errorf("DiffWallets(nil, empty wallet) == %q, want empty", diff)
+	}
+}
@@ -0,0 +1,114 @@
+package currencytest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+//DiffAmount returns a readable difference of got and want (e.g.: got USD 3.33, want USD 3.34 (off by USD -0.01)),
+//returns empty string if they are equal
+func DiffAmount(got currency.Amount, want currency.Amount) string {
+	if got.IsEquals(want) {
+		return ""
+	}
+	difference, err := got.Minus(want)
+	if err != nil {
+		return fmt.Sprintf("got %s, want %s (currencies differ)", got.String(), want.String())
+	}
+	return fmt.Sprintf("got %s, want %s (off by %s)", got.String(), want.String(), difference.String())
+}
+
+//DiffAmounts returns the readable differences of got and want by index, one line per different index,
+//returns empty string if they are equal
+func DiffAmounts(got []currency.Amount, want []currency.Amount) string {
+	var lines []string
+	for i := 0; i < len(got) || i < len(want); i++ {
+		switch {
+		case i >= len(want):
+			lines = append(lines, fmt.Sprintf("[%d] got %s, want nothing", i, got[i].String()))
+		case i >= len(got):
+			lines = append(lines, fmt.Sprintf("[%d] got nothing, want %s", i, want[i].String()))
+		default:
+			if diff := DiffAmount(got[i], want[i]); diff != "" {
+				lines = append(lines, fmt.Sprintf("[%d] %s", i, diff))
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+//DiffWallets returns the readable differences of got and want balances by currency, one line per different currency,
+//returns empty string if they are equal
+func DiffWallets(got *currency.Wallet, want *currency.Wallet) string {
+	gotBalances, wantBalances := balances(got), balances(want)
+	codes := make([]string, 0, len(gotBalances)+len(wantBalances))
+	for code := range gotBalances {
+		codes = append(codes, code)
+	}
+	for code := range wantBalances {
+		if _, exists := gotBalances[code]; !exists {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	var lines []string
+	for _, code := range codes {
+		gotBalance, gotExists := gotBalances[code]
+		wantBalance, wantExists := wantBalances[code]
+		switch {
+		case !wantExists:
+			lines = append(lines, fmt.Sprintf("%s: got %s, want nothing", code, gotBalance.BasicUnitValue()))
+		case !gotExists:
+			lines = append(lines, fmt.Sprintf("%s: got nothing, want %s", code, wantBalance.BasicUnitValue()))
+		case !gotBalance.IsEquals(wantBalance):
+			line := fmt.Sprintf("%s: got %s, want %s", code, gotBalance.BasicUnitValue(), wantBalance.BasicUnitValue())
+			if difference, err := gotBalance.Minus(wantBalance); err == nil {
+				line += " (off by " + difference.BasicUnitValue() + ")"
+			}
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+//AssertAmount reports an error of the test if got is not equal to want
+func AssertAmount(t testing.TB, got currency.Amount, want currency.Amount) {
+	t.Helper()
+	if diff := DiffAmount(got, want); diff != "" {
+		t.Errorf("amount differs: %s", diff)
+	}
+}
+
+//AssertAmounts reports an error of the test if got is not equal to want
+func AssertAmounts(t testing.TB, got []currency.Amount, want []currency.Amount) {
+	t.Helper()
+	if diff := DiffAmounts(got, want); diff != "" {
+		t.Errorf("amounts differ:\n%s", diff)
+	}
+}
+
+//AssertWallet reports an error of the test if the balances of got are not equal to want
+func AssertWallet(t testing.TB, got *currency.Wallet, want *currency.Wallet) {
+	t.Helper()
+	if diff := DiffWallets(got, want); diff != "" {
+		t.Errorf("wallet differs:\n%s", diff)
+	}
+}
+
+//balances returns the non-zero balances of wallet by currency code, nil wallet has no balance
+func balances(wallet *currency.Wallet) map[string]currency.Amount {
+	result := make(map[string]currency.Amount)
+	if wallet == nil {
+		return result
+	}
+	wallet.Each(func(amount currency.Amount) bool {
+		result[amount.CurrencyCode()] = amount
+		return true
+	})
+	return result
+}
@@ -0,0 +1,45 @@
+package currencytest
+
+import (
+	"testing"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+//MustAmount returns the amount of text in the default format of amount (e.g.: USD 1.50) by currency.Factory,
+//fails the test immediately if text is invalid
+func MustAmount(t testing.TB, text string) currency.Amount {
+	t.Helper()
+	return MustAmountIn(t, &currency.Factory, text)
+}
+
+//MustAmountIn returns the amount of text in the default format of amount (e.g.: USD 1.50) by registry,
+//fails the test immediately if text is invalid
+func MustAmountIn(t testing.TB, registry Registry, text string) currency.Amount {
+	t.Helper()
+	amount, err := registry.ParseAmount(text)
+	if err != nil {
+		t.Fatalf("currencytest.MustAmount(%q): %v", text, err)
+	}
+	return amount
+}
+
+//MustAmounts returns the amounts of texts, see MustAmount
+func MustAmounts(t testing.TB, texts ...string) []currency.Amount {
+	t.Helper()
+	amounts := make([]currency.Amount, len(texts))
+	for i, text := range texts {
+		amounts[i] = MustAmount(t, text)
+	}
+	return amounts
+}
+
+//MustWallet returns a wallet holding the amounts of texts, see MustAmount
+func MustWallet(t testing.TB, texts ...string) *currency.Wallet {
+	t.Helper()
+	wallet, err := currency.NewWallet(MustAmounts(t, texts...)...)
+	if err != nil {
+		t.Fatalf("currencytest.MustWallet(%q): %v", texts, err)
+	}
+	return wallet
+}
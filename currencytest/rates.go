@@ -0,0 +1,51 @@
+package currencytest
+
+import (
+	"errors"
+	"strings"
+)
+
+//FixedRates is a rate source of fixed rates keyed by currency pair "FROM/TO" (e.g.: {"USD/CNY": 6.8}),
+//inverse rates are derived, and the rate of same currencies is 1
+type FixedRates map[string]float64
+
+//Rate implements currency.RateSource
+//return error if neither the pair nor its inverse is in rates
+func (rates FixedRates) Rate(fromCurrencyCode string, toCurrencyCode string) (float64, error) {
+	fromCurrencyCode = strings.ToUpper(strings.TrimSpace(fromCurrencyCode))
+	toCurrencyCode = strings.ToUpper(strings.TrimSpace(toCurrencyCode))
+	if fromCurrencyCode == toCurrencyCode {
+		return 1, nil
+	}
+	if rate, exists := rates[fromCurrencyCode+"/"+toCurrencyCode]; exists {
+		return rate, nil
+	}
+	if rate, exists := rates[toCurrencyCode+"/"+fromCurrencyCode]; exists && rate != 0 {
+		return 1 / rate, nil
+	}
+	return 0, errors.New("fx rate of " + fromCurrencyCode + "/" + toCurrencyCode + " is not found")
+}
+
+//ConstantRate is a rate source returning the same rate for all pairs of different currencies
+type ConstantRate float64
+
+//Rate implements currency.RateSource
+func (rate ConstantRate) Rate(fromCurrencyCode string, toCurrencyCode string) (float64, error) {
+	if strings.EqualFold(strings.TrimSpace(fromCurrencyCode), strings.TrimSpace(toCurrencyCode)) {
+		return 1, nil
+	}
+	return float64(rate), nil
+}
+
+//FailingRates is a rate source always returning Err, for the error paths of code using rate sources
+type FailingRates struct {
+	Err error
+}
+
+//Rate implements currency.RateSource
+func (rates FailingRates) Rate(fromCurrencyCode string, toCurrencyCode string) (float64, error) {
+	if rates.Err == nil {
+		return 0, errors.New("fx rate of " + fromCurrencyCode + "/" + toCurrencyCode + " is not available")
+	}
+	return 0, rates.Err
+}
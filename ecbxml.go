@@ -0,0 +1,144 @@
+package currency
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+)
+
+//ECB euro foreign exchange reference rates XML, please refer to https://www.ecb.europa.eu/stats/policy_and_exchange_rates/euro_reference_exchange_rates/html/index.en.html
+
+//EcbDailyXmlURL is the URL of ECB daily reference rates XML
+const EcbDailyXmlURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+
+//EcbHistoricalXmlURL is the URL of ECB historical reference rates XML
+const EcbHistoricalXmlURL = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+
+//ecbRate represents the innermost Cube element in ECB XML, 1 EUR = rate currency
+type ecbRate struct {
+	Currency string `xml:"currency,attr"`
+	Rate     string `xml:"rate,attr"`
+}
+
+//ecbDay represents the Cube element with time attribute in ECB XML
+type ecbDay struct {
+	Time  string    `xml:"time,attr"`
+	Rates []ecbRate `xml:"Cube"`
+}
+
+//ecbCube represents the outermost Cube element in ECB XML
+type ecbCube struct {
+	Days []ecbDay `xml:"Cube"`
+}
+
+//ecbXml represents the root node (gesmes:Envelope) of ECB XML
+type ecbXml struct {
+	Cube ecbCube `xml:"Cube"`
+}
+
+//ecbDailyRates is the parsed reference rates of a day
+type ecbDailyRates struct {
+	day   time.Time
+	rates map[string]float64 //key is currency code, 1 EUR = value currency
+}
+
+//currencyCodes returns the currency codes of rates in ascending order
+func (daily ecbDailyRates) currencyCodes() []string {
+	codes := make([]string, 0, len(daily.rates))
+	for code := range daily.rates {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+//LoadEcbXmlToRateTable load the latest day of ECB reference rates XML (daily or historical) into table as EUR rates
+//returns the day of loaded rates, table is not changed if an error is returned
+//return error if the XML is malformed or contains no rate
+//return error if any currency code or rate of XML is invalid
+func LoadEcbXmlToRateTable(reader io.Reader, table *RateTable) (time.Time, error) {
+	days, err := parseEcbXml(reader)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	latest := days[0]
+	for _, day := range days[1:] {
+		if day.day.After(latest.day) {
+			latest = day
+		}
+	}
+	for _, currencyCode := range latest.currencyCodes() {
+		if err := table.SetRate("EUR", currencyCode, latest.rates[currencyCode]); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return latest.day, nil
+}
+
+//LoadEcbXmlToRateHistory load all days of ECB reference rates XML (daily or historical) into history as EUR rates,
+//the rates of a day are effective from 00:00 UTC of that day, history is not changed if an error is returned
+//return error if the XML is malformed or contains no rate
+//return error if any currency code or rate of XML is invalid
+func LoadEcbXmlToRateHistory(reader io.Reader, history *RateHistory) error {
+	days, err := parseEcbXml(reader)
+	if err != nil {
+		return err
+	}
+
+	for _, day := range days {
+		for _, currencyCode := range day.currencyCodes() {
+			if err := history.AddRate("EUR", currencyCode, day.day, day.rates[currencyCode]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+//parseEcbXml parse ECB reference rates XML, the currency codes and rates are validated,
+//so that they can be loaded into a rate table or history without error
+//return error if the XML is malformed or contains no rate
+//return error if any currency code is not a three-letter alphabetic code other than EUR, or any rate is not a finite value greater than 0
+func parseEcbXml(reader io.Reader) ([]ecbDailyRates, error) {
+	var ecbXml ecbXml
+	err := xml.NewDecoder(reader).Decode(&ecbXml)
+	if err != nil {
+		return nil, err
+	}
+
+	var days []ecbDailyRates
+	for _, day := range ecbXml.Cube.Days {
+		dayTime, err := time.Parse("2006-01-02", day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("ECB XML time %q is invalid", day.Time)
+		}
+		rates := make(map[string]float64, len(day.Rates))
+		for _, ecbRate := range day.Rates {
+			rate, err := strconv.ParseFloat(ecbRate.Rate, 64)
+			if err != nil {
+				return nil, fmt.Errorf("ECB XML rate %q of %s at %s is not a numberic value", ecbRate.Rate, ecbRate.Currency, day.Time)
+			}
+			currencyCode, _, err := normalizeCurrencyPair(ecbRate.Currency, "EUR")
+			if err != nil || currencyCode == "EUR" {
+				return nil, fmt.Errorf("ECB XML currency %q at %s is invalid", ecbRate.Currency, day.Time)
+			}
+			if !(rate > 0) || math.IsInf(rate, 0) {
+				return nil, fmt.Errorf("ECB XML rate %q of %s at %s must be a finite value greater than 0", ecbRate.Rate, ecbRate.Currency, day.Time)
+			}
+			rates[currencyCode] = rate
+		}
+		if len(rates) > 0 {
+			days = append(days, ecbDailyRates{dayTime, rates})
+		}
+	}
+	if len(days) == 0 {
+		return nil, errors.New("ECB XML contains no rate")
+	}
+	return days, nil
+}
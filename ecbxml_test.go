@@ -0,0 +1,87 @@
+package currency
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("EUR", 2)
+}
+
+func TestLoadEcbXmlToRateHistory(t *testing.T) {
+	//case 1: vendored historical file
+	file, err := os.Open("testdata/eurofxref-hist.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	history := NewRateHistory(LookupLastBefore, 0)
+	err = LoadEcbXmlToRateHistory(file, history)
+	if err != nil {
+		t.Fatalf("LoadEcbXmlToRateHistory() return an error: %v", err)
+	}
+	asOf := time.Date(2020, 1, 2, 16, 0, 0, 0, time.UTC)
+	rate, _, _ := history.Lookup("EUR", "USD", asOf)
+	if rate != 1.1193 {
+		t.Errorf("Lookup(\"EUR\", \"USD\", %s) == %f, want 1.1193", asOf, rate)
+	}
+
+	//case 2: malformed
+	err = LoadEcbXmlToRateHistory(strings.NewReader("<Cube><Cube time=\"2020-01-02\"><Cube currency=\"USD\" rate=\"x\"/></Cube></Cube>"), history)
+	if err == nil {
+		t.Errorf("LoadEcbXmlToRateHistory() with malformed rate should be return an error, but no error return")
+	}
+
+	//case 3: invalid rate of a later day, history is not changed
+	history = NewRateHistory(LookupLastBefore, 0)
+	err = LoadEcbXmlToRateHistory(strings.NewReader(`<Cube><Cube time="2020-01-02"><Cube currency="USD" rate="1.1"/></Cube><Cube time="2020-01-03"><Cube currency="JPY" rate="0"/></Cube></Cube>`), history)
+	if _, _, lookupErr := history.Lookup("EUR", "USD", asOf); err == nil || lookupErr == nil {
+		t.Errorf("LoadEcbXmlToRateHistory() with rate 0 == %v, want an error and no rate loaded", err)
+	}
+}
+
+func TestLoadEcbXmlToRateTable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/eurofxref-hist.xml")
+	}))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	table := NewRateTable("EUR")
+	day, err := LoadEcbXmlToRateTable(resp.Body, table)
+	if err != nil {
+		t.Fatalf("LoadEcbXmlToRateTable() return an error: %v", err)
+	}
+	if want := time.Date(2020, 1, 3, 0, 0, 0, 0, time.UTC); !day.Equal(want) {
+		t.Errorf("LoadEcbXmlToRateTable() == %s, want %s", day, want)
+	}
+
+	eurAmount, _ := Factory.NewAmountInBasicUnit("EUR", "100")
+	got, _ := eurAmount.FxByRateSource("USD", table)
+	want, _ := Factory.NewAmountInBasicUnit("USD", "111.47")
+	if !want.IsEquals(got) {
+		t.Errorf("%s FxByRateSource(\"USD\") == %s, want %s", eurAmount.String(), got.String(), want.String())
+	}
+
+	//case 2: invalid rate or currency, table is not changed
+	for _, cube := range []string{`<Cube currency="USD" rate="1.1"/><Cube currency="JPY" rate="0"/>`, `<Cube currency="USD" rate="1.1"/><Cube currency="JPY" rate="+Inf"/>`, `<Cube currency="USD" rate="1.1"/><Cube currency="EUR" rate="1"/>`} {
+		for i := 0; i < 10; i++ {
+			table := NewRateTable("EUR")
+			_, err := LoadEcbXmlToRateTable(strings.NewReader(`<Cube><Cube time="2020-01-02">`+cube+`</Cube></Cube>`), table)
+			if _, rateErr := table.Rate("EUR", "USD"); err == nil || rateErr == nil {
+				t.Fatalf("LoadEcbXmlToRateTable(%s) == %v, want an error and no rate loaded", cube, err)
+			}
+		}
+	}
+}
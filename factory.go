@@ -3,31 +3,77 @@ package currency
 import (
 	"encoding/xml"
 	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
 )
 
 //Factory is currency factory
-var Factory = factory{currencyMap: make(map[string]Currency), mapLocker: new(sync.Mutex), initLocker: new(sync.Mutex)}
+var Factory = CurrencyFactory{currencyMap: make(map[string]Currency), mapLocker: new(sync.RWMutex), initLocker: new(sync.Mutex)}
 var currencyCodeReg, _ = regexp.Compile("^[A-Z]{3}$")
 
-type factory struct {
+//CurrencyFactory is a registry of currencies creating amounts of them, Factory is the default one
+type CurrencyFactory struct {
 	currencyMap map[string]Currency // key is ISO 4217 three-letter alphabetic code
-	mapLocker   *sync.Mutex
+	mapLocker   *sync.RWMutex       //guards currencyMap, lookups take the read lock
 
 	initFlag   bool
 	initLocker *sync.Mutex
+
+	strictPrecision bool //true if NewAmountInBasicUnit refuses values with more decimals than currency allows
+}
+
+//FactoryOption is an option of factory
+type FactoryOption func(factory *CurrencyFactory)
+
+//WithStrictPrecision is the option that NewAmountInBasicUnit (and ParseAmount, Amount.UnmarshalJSON of Factory)
+//returns *PrecisionError instead of rounding values with more decimals than currency allows
+func WithStrictPrecision() FactoryOption {
+	return func(factory *CurrencyFactory) {
+		factory.strictPrecision = true
+	}
+}
+
+//NewFactory create a new currency factory without any currency, the currencies registered in it are not visible to Factory.
+//It's not fully isolated: amounts created by it use Factory when looking up the target currency of Fx or decoding JSON
+func NewFactory(options ...FactoryOption) *CurrencyFactory {
+	factory := &CurrencyFactory{currencyMap: make(map[string]Currency), mapLocker: new(sync.RWMutex), initLocker: new(sync.Mutex)}
+	factory.SetOptions(options...)
+	return factory
+}
+
+//SetOptions set the options of factory (e.g.: Factory.SetOptions(WithStrictPrecision())),
+//it should be called before the factory is used by other goroutines
+func (factory *CurrencyFactory) SetOptions(options ...FactoryOption) {
+	for _, option := range options {
+		option(factory)
+	}
+}
+
+//PrecisionError is returned by strict construction if the value has more decimals than currency allows
+type PrecisionError struct {
+	CurrencyCode    string
+	BasicUnitValue  string
+	MinorUnitDigits uint8
+	ExcessDigits    int //the number of decimals beyond MinorUnitDigits, trailing zeros are not counted
+}
+
+//Error implements error
+func (err *PrecisionError) Error() string {
+	return fmt.Sprintf("basicUnitValue %s has %d excess decimal digits, %s allows %d", err.BasicUnitValue, err.ExcessDigits, err.CurrencyCode, err.MinorUnitDigits)
 }
 
 //NewCurrency create a new currency object
 //return error if the code is not a three-letter alphabetic code
-func (factory *factory) NewCurrency(currencyCode string, minorUnitDigits uint8) (Currency, error) {
+func (factory *CurrencyFactory) NewCurrency(currencyCode string, minorUnitDigits uint8) (Currency, error) {
 	currencyCode = strings.ToUpper(strings.TrimSpace(currencyCode))
-	currency, exists := factory.currencyMap[currencyCode]
+	currency, exists := factory.lookup(currencyCode)
 	if exists {
 		return currency, nil
 	}
@@ -48,7 +94,7 @@ func (factory *factory) NewCurrency(currencyCode string, minorUnitDigits uint8)
 
 //InitFromOnlineIso4217Xml init currencies from online ISO 4217 XML
 //URL: https://www.currency-iso.org/dam/downloads/lists/list_one.xml
-func (factory *factory) InitFromOnlineIso4217Xml() error {
+func (factory *CurrencyFactory) InitFromOnlineIso4217Xml() error {
 	if factory.initFlag {
 		return nil
 	}
@@ -66,7 +112,17 @@ func (factory *factory) InitFromOnlineIso4217Xml() error {
 	}
 	defer resp.Body.Close()
 
-	iso4217XmlBytes, err := ioutil.ReadAll(resp.Body)
+	err = factory.InitFromIso4217Xml(resp.Body)
+	if err != nil {
+		return err
+	}
+	factory.initFlag = true
+	return nil
+}
+
+//InitFromIso4217Xml init currencies from ISO 4217 XML, in the format of https://www.currency-iso.org/dam/downloads/lists/list_one.xml
+func (factory *CurrencyFactory) InitFromIso4217Xml(reader io.Reader) error {
+	iso4217XmlBytes, err := ioutil.ReadAll(reader)
 	if err != nil {
 		return err
 	}
@@ -86,44 +142,89 @@ func (factory *factory) InitFromOnlineIso4217Xml() error {
 		}
 		factory.NewCurrency(ccyNtry.Ccy, uint8(minorUnitDigits))
 	}
-	factory.initFlag = true
 	return nil
 }
 
+//InitFromBuiltinIso4217 init currencies from the built-in copy of ISO 4217 list one, works offline
+func (factory *CurrencyFactory) InitFromBuiltinIso4217() {
+	for _, currency := range iso4217Currencies {
+		factory.NewCurrency(currency.code, currency.minorUnitDigits)
+	}
+}
+
+//Currencies returns all currencies managed by factory, sorted by code
+func (factory *CurrencyFactory) Currencies() []Currency {
+	factory.mapLocker.RLock()
+	defer factory.mapLocker.RUnlock()
+	currencies := make([]Currency, 0, len(factory.currencyMap))
+	for _, currency := range factory.currencyMap {
+		currencies = append(currencies, currency)
+	}
+	sort.Slice(currencies, func(i, j int) bool { return currencies[i].code < currencies[j].code })
+	return currencies
+}
+
 //NewAmountInBasicUnit create a new amount object by using basic unit value
 //return error if currencyCode is not a three-letter alphabetic code
 //return error if currencyCode is not managed by factory
-//return error if basicUnitValue is not a numberic value
-func (factory *factory) NewAmountInBasicUnit(currencyCode string, basicUnitValue string) (Amount, error) {
+//return error if basicUnitValue is not a decimal value (e.g.: -1.5, 1234.5678, 1.5e3)
+//return error if the value is out of range
+//the value is rounded to minor unit by banker's rounding algorithm on its decimal digits (e.g.: USD 1.015 => 1.02),
+//unless the factory has option WithStrictPrecision, then *PrecisionError is returned instead
+func (factory *CurrencyFactory) NewAmountInBasicUnit(currencyCode string, basicUnitValue string) (Amount, error) {
+	return factory.newAmountInBasicUnit(currencyCode, basicUnitValue, RoundHalfEven, factory.strictPrecision)
+}
+
+//NewAmountInBasicUnitStrict create a new amount object by using basic unit value without rounding
+//return *PrecisionError if basicUnitValue has more decimals than currency allows (e.g.: USD 1.567), trailing zeros are allowed (e.g.: USD 1.500)
+//return error in the same cases as NewAmountInBasicUnit
+func (factory *CurrencyFactory) NewAmountInBasicUnitStrict(currencyCode string, basicUnitValue string) (Amount, error) {
+	return factory.newAmountInBasicUnit(currencyCode, basicUnitValue, RoundHalfEven, true)
+}
+
+//NewAmountInBasicUnitWithMode create a new amount object by using basic unit value,
+//rounded to minor unit by mode explicitly, even if the factory has option WithStrictPrecision
+//return error if mode is not one of the rounding modes (e.g.: RoundingMode(9))
+//return error in the same cases as NewAmountInBasicUnit
+func (factory *CurrencyFactory) NewAmountInBasicUnitWithMode(currencyCode string, basicUnitValue string, mode RoundingMode) (Amount, error) {
+	if err := mode.validate(); err != nil {
+		return Amount{}, err
+	}
+	return factory.newAmountInBasicUnit(currencyCode, basicUnitValue, mode, false)
+}
+
+//newAmountInBasicUnit create a new amount object by using basic unit value rounded by mode
+//return *PrecisionError if strict and basicUnitValue has more decimals than currency allows
+func (factory *CurrencyFactory) newAmountInBasicUnit(currencyCode string, basicUnitValue string, mode RoundingMode, strict bool) (Amount, error) {
 	currencyCode = strings.ToUpper(strings.TrimSpace(currencyCode))
 	if !currencyCodeReg.MatchString(currencyCode) {
 		return Amount{}, errors.New("the currencyCode is not a three-letter alphabetic code")
 	}
-	currency, exists := factory.currencyMap[currencyCode]
+	currency, exists := factory.lookup(currencyCode)
 	if !exists {
 		return Amount{}, errors.New("currency code is not found")
 	}
 
 	basicUnitValue = strings.TrimSpace(basicUnitValue)
-	floatValue, err := strconv.ParseFloat(basicUnitValue, 64)
+	minorUnitValue, excessDigits, err := parseBasicUnitValue(basicUnitValue, currency.minorUnitDigits, mode)
 	if err != nil {
-		return Amount{}, errors.New("basicUnitValue is not a numberic value")
+		return Amount{}, err
 	}
-
-	amount := newZeroAmount(currency)
-	amount.setBasicUnitValue(floatValue)
-	return amount, nil
+	if strict && excessDigits > 0 {
+		return Amount{}, &PrecisionError{currency.code, basicUnitValue, currency.minorUnitDigits, excessDigits}
+	}
+	return Amount{currency, minorUnitValue}, nil
 }
 
 //NewAmountInMinorUnit create a new amount object by using minor unit value
 //return error if currencyCode is not a three-letter alphabetic code
 //return error if currencyCode is not managed by factory
-func (factory *factory) NewAmountInMinorUnit(currencyCode string, minorUnitValue int64) (Amount, error) {
+func (factory *CurrencyFactory) NewAmountInMinorUnit(currencyCode string, minorUnitValue int64) (Amount, error) {
 	currencyCode = strings.ToUpper(strings.TrimSpace(currencyCode))
 	if !currencyCodeReg.MatchString(currencyCode) {
 		return Amount{}, errors.New("the currencyCode is not three-letter alphabetic code")
 	}
-	currency, exists := factory.currencyMap[currencyCode]
+	currency, exists := factory.lookup(currencyCode)
 	if !exists {
 		return Amount{}, errors.New("currency code is not found")
 	}
@@ -136,15 +237,35 @@ func (factory *factory) NewAmountInMinorUnit(currencyCode string, minorUnitValue
 //GetCurrencyByCode return a Currency object by using  a three-letter alphabetic code
 //return error if currencyCode is not a three-letter alphabetic code
 //return error if currencyCode is not managed by factory
-func (factory *factory) GetCurrencyByCode(currencyCode string) (Currency, error) {
+func (factory *CurrencyFactory) GetCurrencyByCode(currencyCode string) (Currency, error) {
 	currencyCode = strings.ToUpper(strings.TrimSpace(currencyCode))
 	if !currencyCodeReg.MatchString(currencyCode) {
 		return Currency{}, errors.New("the currencyCode is not three-letter alphabetic code")
 	}
 
-	currency, exists := factory.currencyMap[currencyCode]
+	currency, exists := factory.lookup(currencyCode)
 	if !exists {
 		return Currency{}, errors.New("currency code is not found")
 	}
 	return currency, nil
 }
+
+//ParseAmount create a new amount object from the default format string of amount (e.g.: USD 1.00)
+//return error if text is not in the format of currency code and basic unit value separated by spaces
+//return error if currency code is not managed by factory
+//return error if the value is not a numberic value
+func (factory *CurrencyFactory) ParseAmount(text string) (Amount, error) {
+	fields := strings.Fields(text)
+	if len(fields) != 2 {
+		return Amount{}, errors.New("amount text is not in the format of \"<currency code> <value>\"")
+	}
+	return factory.NewAmountInBasicUnit(fields[0], fields[1])
+}
+
+//lookup returns the currency of upper case code under the read lock, false if not found
+func (factory *CurrencyFactory) lookup(currencyCode string) (Currency, bool) {
+	factory.mapLocker.RLock()
+	currency, exists := factory.currencyMap[currencyCode]
+	factory.mapLocker.RUnlock()
+	return currency, exists
+}
@@ -1,6 +1,12 @@
 package currency
 
-import "testing"
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+)
 
 func init() {
 	Factory.NewCurrency("USD", 2)
@@ -92,3 +98,239 @@ func TestNewAmountInMinorUnit(t *testing.T) {
 		t.Errorf("Factory.NewAmountInMinorUnit(%s, %d) == %s, want %s", currencyCode, minorUnitValue, got, want)
 	}
 }
+
+func TestInitFromIso4217Xml(t *testing.T) {
+	xml := `<ISO_4217 Pblshd="2018-08-29"><CcyTbl>
+<CcyNtry><CtryNm>BAHRAIN</CtryNm><CcyNm>Bahraini Dinar</CcyNm><Ccy>BHD</Ccy><CcyNbr>048</CcyNbr><CcyMnrUnts>3</CcyMnrUnts></CcyNtry>
+<CcyNtry><CtryNm>ZZ08_Gold</CtryNm><CcyNm IsFund="true">Gold</CcyNm><Ccy>XAU</Ccy><CcyNbr>959</CcyNbr><CcyMnrUnts>N.A.</CcyMnrUnts></CcyNtry>
+</CcyTbl></ISO_4217>`
+	err := Factory.InitFromIso4217Xml(strings.NewReader(xml))
+	got, _ := Factory.GetCurrencyByCode("BHD")
+	want := Currency{"BHD", 3}
+	if err != nil || want != got {
+		t.Errorf("Factory.InitFromIso4217Xml() then GetCurrencyByCode(\"BHD\") == %v %v, want %v", got, err, want)
+	}
+}
+
+func TestInitFromBuiltinIso4217(t *testing.T) {
+	Factory.InitFromBuiltinIso4217()
+	for _, want := range []Currency{{"JPY", 0}, {"KWD", 3}, {"CLF", 4}, {"EUR", 2}} {
+		got, err := Factory.GetCurrencyByCode(want.code)
+		if err != nil || want != got {
+			t.Errorf("Factory.GetCurrencyByCode(%s) == %v %v, want %v", want.code, got, err, want)
+		}
+	}
+
+	currencies := Factory.Currencies()
+	if len(currencies) < len(iso4217Currencies) {
+		t.Errorf("Factory.Currencies() has %d currencies, want at least %d", len(currencies), len(iso4217Currencies))
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	//case 1:
+	got, _ := Factory.ParseAmount(" usd  -1.5 ")
+	if got.String() != "USD -1.50" {
+		t.Errorf("Factory.ParseAmount(\" usd  -1.5 \") == %s, want USD -1.50", got.String())
+	}
+
+	//case 2:
+	_, err := Factory.ParseAmount("USD1.5")
+	if err == nil {
+		t.Errorf("Factory.ParseAmount(\"USD1.5\"), shoule be return an error, but no error return")
+	}
+}
+
+func TestNewFactory(t *testing.T) {
+	factory := NewFactory()
+	factory.NewCurrency("ZZZ", 2)
+
+	//case 1:
+	_, err := Factory.GetCurrencyByCode("ZZZ")
+	if err == nil {
+		t.Errorf("Factory.GetCurrencyByCode(\"ZZZ\") registered by NewFactory() should be return an error, but no error return")
+	}
+
+	//case 2:
+	_, err = factory.GetCurrencyByCode("USD")
+	if err == nil {
+		t.Errorf("NewFactory().GetCurrencyByCode(\"USD\") should be return an error, but no error return")
+	}
+	amount, err := factory.NewAmountInBasicUnit("zzz", "1.5")
+	if err != nil || amount.String() != "ZZZ 1.50" {
+		t.Errorf("NewFactory().NewAmountInBasicUnit(\"zzz\", \"1.5\") == %s %v, want ZZZ 1.50", amount.String(), err)
+	}
+}
+
+func TestNewAmountInBasicUnitStrict(t *testing.T) {
+	//case 1:
+	got, err := Factory.NewAmountInBasicUnitStrict("usd", "1.500")
+	if err != nil || got.String() != "USD 1.50" {
+		t.Errorf("Factory.NewAmountInBasicUnitStrict(\"usd\", \"1.500\") == %s %v, want USD 1.50", got.String(), err)
+	}
+
+	//case 2:
+	_, err = Factory.NewAmountInBasicUnitStrict("usd", "1.567")
+	want := &PrecisionError{"USD", "1.567", 2, 1}
+	precisionErr, ok := err.(*PrecisionError)
+	if !ok || *precisionErr != *want {
+		t.Errorf("Factory.NewAmountInBasicUnitStrict(\"usd\", \"1.567\") == %v, want %v", err, want)
+	}
+
+	//case 3:
+	factory := NewFactory(WithStrictPrecision())
+	factory.NewCurrency("USD", 2)
+	_, err = factory.ParseAmount("USD 0.0001")
+	if precisionErr, ok := err.(*PrecisionError); !ok || precisionErr.ExcessDigits != 2 {
+		t.Errorf("NewFactory(WithStrictPrecision()).ParseAmount(\"USD 0.0001\") == %v, want 2 excess digits", err)
+	}
+	got, err = factory.NewAmountInBasicUnitWithMode("USD", "1.561", RoundDown)
+	if err != nil || got.String() != "USD 1.56" {
+		t.Errorf("NewFactory(WithStrictPrecision()).NewAmountInBasicUnitWithMode(\"USD\", \"1.561\", RoundDown) == %s %v, want USD 1.56", got.String(), err)
+	}
+}
+
+func TestNewAmountInBasicUnitWithMode(t *testing.T) {
+	cases := []struct {
+		basicUnitValue string
+		mode           RoundingMode
+		want           string
+	}{
+		{"1.565", RoundHalfEven, "USD 1.56"},
+		{"1.565", RoundHalfUp, "USD 1.57"},
+		{"-1.561", RoundCeiling, "USD -1.56"},
+		{"-1.561", RoundFloor, "USD -1.57"},
+		{"1.561", RoundUp, "USD 1.57"},
+	}
+	for _, c := range cases {
+		got, err := Factory.NewAmountInBasicUnitWithMode("USD", c.basicUnitValue, c.mode)
+		if err != nil || got.String() != c.want {
+			t.Errorf("Factory.NewAmountInBasicUnitWithMode(\"USD\", %q, %s) == %s %v, want %s", c.basicUnitValue, c.mode, got.String(), err, c.want)
+		}
+	}
+
+	_, err := Factory.NewAmountInBasicUnitWithMode("USD", "1.565", RoundingMode(len(roundingModeNames)))
+	if err == nil {
+		t.Errorf("Factory.NewAmountInBasicUnitWithMode(\"USD\", \"1.565\", %s) should be return an error, but no error return", RoundingMode(len(roundingModeNames)))
+	}
+}
+
+func TestFactoryAllocations(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		Factory.GetCurrencyByCode("USD")
+		Factory.NewAmountInMinorUnit("USD", 150)
+	})
+	if allocs != 0 {
+		t.Errorf("Factory.GetCurrencyByCode and NewAmountInMinorUnit allocate %v times, want 0", allocs)
+	}
+}
+
+//builtinIso4217Xml returns the ISO 4217 XML of built-in currencies
+func builtinIso4217Xml() []byte {
+	var buffer bytes.Buffer
+	buffer.WriteString(`<ISO_4217 Pblshd="2018-08-29"><CcyTbl>`)
+	for _, currency := range iso4217Currencies {
+		fmt.Fprintf(&buffer, "<CcyNtry><CtryNm>COUNTRY</CtryNm><CcyNm>Currency</CcyNm><Ccy>%s</Ccy><CcyNbr>000</CcyNbr><CcyMnrUnts>%d</CcyMnrUnts></CcyNtry>\n",
+			currency.code, currency.minorUnitDigits)
+	}
+	buffer.WriteString(`</CcyTbl></ISO_4217>`)
+	return buffer.Bytes()
+}
+
+func BenchmarkNewAmountInBasicUnit(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Factory.NewAmountInBasicUnit("usd", "1234.567")
+	}
+}
+
+func BenchmarkNewAmountInMinorUnit(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Factory.NewAmountInMinorUnit("USD", 123457)
+	}
+}
+
+func BenchmarkParseAmount(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Factory.ParseAmount("USD 1234.57")
+	}
+}
+
+func BenchmarkGetCurrencyByCode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Factory.GetCurrencyByCode("USD")
+	}
+}
+
+//BenchmarkGetCurrencyByCodeParallel looks up currencies while a writer keeps registering (and removing) other currencies
+func BenchmarkGetCurrencyByCodeParallel(b *testing.B) {
+	registry := NewFactory()
+	registry.NewCurrency("USD", 2)
+	registry.NewCurrency("CNY", 2)
+	codes := []string{"USD", "CNY", "usd", " cny "}
+
+	done := make(chan struct{})
+	var writer sync.WaitGroup
+	writer.Add(1)
+	go func() {
+		defer writer.Done()
+		for i := 0; ; i++ {
+			select {
+			case <-done:
+				return
+			default:
+			}
+			code := string([]byte{'Q', byte('A' + i/26%26), byte('A' + i%26)})
+			registry.NewCurrency(code, 2)
+			registry.mapLocker.Lock()
+			delete(registry.currencyMap, code) //keep the next NewCurrency a write
+			registry.mapLocker.Unlock()
+		}
+	}()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			registry.GetCurrencyByCode(codes[i%len(codes)])
+		}
+	})
+	close(done)
+	writer.Wait()
+}
+
+func BenchmarkNewAmountParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Factory.NewAmountInBasicUnit("USD", "1.50")
+		}
+	})
+}
+
+func BenchmarkCurrenciesParallel(b *testing.B) {
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			Factory.Currencies()
+		}
+	})
+}
+
+func BenchmarkInitFromIso4217Xml(b *testing.B) {
+	xml := builtinIso4217Xml()
+	b.SetBytes(int64(len(xml)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Factory.InitFromIso4217Xml(bytes.NewReader(xml))
+	}
+}
+
+func BenchmarkInitFromBuiltinIso4217(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Factory.InitFromBuiltinIso4217()
+	}
+}
@@ -0,0 +1,94 @@
+package currency
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+//localeFormat is the number format of a locale
+type localeFormat struct {
+	decimalSeparator string
+	groupSeparator   string
+	codeAfterNumber  bool //true if currency code follows the number (e.g.: 1.234,56 EUR)
+}
+
+//localeFormats is the supported locales, key is the BCP 47 language tag
+var localeFormats = map[string]localeFormat{
+	"en-US": {".", ",", false},
+	"en-GB": {".", ",", false},
+	"en-AU": {".", ",", false},
+	"en-CA": {".", ",", false},
+	"zh-CN": {".", ",", false},
+	"ja-JP": {".", ",", false},
+	"ko-KR": {".", ",", false},
+	"de-DE": {",", ".", true},
+	"de-AT": {",", "\u00a0", true},
+	"de-CH": {".", "\u2019", false},
+	"es-ES": {",", ".", true},
+	"it-IT": {",", ".", true},
+	"nl-NL": {",", ".", false},
+	"pt-BR": {",", ".", false},
+	"fr-FR": {",", "\u202f", true},
+	"fr-CA": {",", "\u00a0", true},
+	"ru-RU": {",", "\u00a0", true},
+	"pl-PL": {",", "\u00a0", true},
+	"sv-SE": {",", "\u00a0", true},
+}
+
+//Locales returns the sorted language tags of supported locales
+func Locales() []string {
+	locales := make([]string, 0, len(localeFormats))
+	for locale := range localeFormats {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+//Format returns the format string of amount in locale (e.g.: en-US USD 1,234.56, de-DE 1.234,56 USD)
+//the locale is a BCP 47 language tag, "_" is accepted as separator (e.g.: de_DE)
+//return error if locale is not supported
+func (amount Amount) Format(locale string) (string, error) {
+	format, exists := localeFormats[normalizeLocale(locale)]
+	if !exists {
+		return "", errors.New("locale " + locale + " is not supported")
+	}
+
+	value := amount.BasicUnitValue()
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign, value = "-", value[1:]
+	}
+	integer, fraction := value, ""
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		integer, fraction = value[:i], value[i+1:]
+	}
+
+	var builder strings.Builder
+	builder.WriteString(sign)
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			builder.WriteString(format.groupSeparator)
+		}
+		builder.WriteRune(digit)
+	}
+	if fraction != "" {
+		builder.WriteString(format.decimalSeparator)
+		builder.WriteString(fraction)
+	}
+
+	if format.codeAfterNumber {
+		return builder.String() + " " + amount.curreny.Code(), nil
+	}
+	return amount.curreny.Code() + " " + builder.String(), nil
+}
+
+//normalizeLocale returns the locale in the form of language-REGION (e.g.: de_de => de-DE)
+func normalizeLocale(locale string) string {
+	parts := strings.Split(strings.Replace(strings.TrimSpace(locale), "_", "-", -1), "-")
+	if len(parts) != 2 {
+		return locale
+	}
+	return strings.ToLower(parts[0]) + "-" + strings.ToUpper(parts[1])
+}
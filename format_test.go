@@ -0,0 +1,45 @@
+package currency
+
+import "testing"
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("EUR", 2)
+	Factory.NewCurrency("JPY", 0)
+}
+
+func TestFormat(t *testing.T) {
+	cases := []struct {
+		currencyCode string
+		value        string
+		locale       string
+		want         string
+	}{
+		{"USD", "1234567.891", "en-US", "USD 1,234,567.89"},
+		{"EUR", "-1234.5", "de_DE", "-1.234,50 EUR"},
+		{"EUR", "999.99", "fr-FR", "999,99 EUR"},
+		{"EUR", "1000", "fr-FR", "1 000,00 EUR"},
+		{"JPY", "1234", "ja-JP", "JPY 1,234"},
+	}
+	for _, c := range cases {
+		amount, _ := Factory.NewAmountInBasicUnit(c.currencyCode, c.value)
+		got, _ := amount.Format(c.locale)
+		if got != c.want {
+			t.Errorf("%s Format(%s) == %q, want %q", amount.String(), c.locale, got, c.want)
+		}
+	}
+
+	amount, _ := Factory.NewAmountInBasicUnit("USD", "1")
+	_, err := amount.Format("xx-XX")
+	if err == nil {
+		t.Errorf("%s Format(\"xx-XX\") should be return an error, but no error return", amount.String())
+	}
+}
+
+func BenchmarkFormat(b *testing.B) {
+	amount, _ := Factory.NewAmountInBasicUnit("EUR", "-1234567.89")
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		amount.Format("de-DE")
+	}
+}
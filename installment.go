@@ -0,0 +1,122 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+//InstallmentFrequency is the interval between due dates of installments
+type InstallmentFrequency uint8
+
+const (
+	//Monthly installments are due on the same day of each month, clamped to the end of shorter months
+	Monthly InstallmentFrequency = iota
+	//Weekly installments are due every 7 days
+	Weekly
+	//BiWeekly installments are due every 14 days
+	BiWeekly
+)
+
+//RemainderPlacement is the installment absorbing the remaining minor units of an uneven split
+type RemainderPlacement uint8
+
+const (
+	//RemainderToFirst adds the whole remainder to the first installment (e.g.: 10.00 in 7 => 1.48, 1.42 x 6)
+	RemainderToFirst RemainderPlacement = iota
+	//RemainderToLast adds the whole remainder to the last installment (e.g.: 10.00 in 7 => 1.42 x 6, 1.48)
+	RemainderToLast
+)
+
+//InstallmentPlan is the plan of splitting an amount into scheduled installments
+type InstallmentPlan struct {
+	Count       int                  //the number of installments, excluding down payment
+	Frequency   InstallmentFrequency //the interval between due dates
+	FirstDue    time.Time            //the due date of first installment
+	EndOfMonth  bool                 //for Monthly, if FirstDue is the last day of month, all installments are due on the last days of months
+	Remainder   RemainderPlacement   //the installment absorbing the remainder
+	DownPayment Amount               //optional, the zero value Amount means no down payment
+	DownDue     time.Time            //the due date of down payment, FirstDue if zero
+}
+
+//Installment is a scheduled payment
+type Installment struct {
+	Number int //0 for down payment, starts from 1 for installments
+	Due    time.Time
+	Amount Amount
+}
+
+//Schedule splits total into installments by plan, the installments and down payment sum up to total exactly
+//return error if total is not greater than 0, or count is not greater than 0
+//return error if frequency or remainder placement is unknown
+//return error if down payment is not in the currency of total, or not less than total
+func (plan InstallmentPlan) Schedule(total Amount) ([]Installment, error) {
+	if total.minorUnitValue <= 0 {
+		return nil, errors.New("installment total must be greater than 0")
+	}
+	if plan.Count <= 0 {
+		return nil, errors.New("installment count must be greater than 0")
+	}
+	if plan.Frequency > BiWeekly {
+		return nil, fmt.Errorf("installment frequency %d is unknown", plan.Frequency)
+	}
+	if plan.Remainder > RemainderToLast {
+		return nil, fmt.Errorf("installment remainder placement %d is unknown", plan.Remainder)
+	}
+
+	var schedule []Installment
+	financed := total
+	if plan.DownPayment.curreny.Code() != "" && plan.DownPayment.minorUnitValue != 0 {
+		if plan.DownPayment.curreny.Code() != total.curreny.Code() {
+			return nil, errors.New("down payment curreny is not same as total")
+		}
+		if plan.DownPayment.minorUnitValue < 0 || plan.DownPayment.minorUnitValue >= total.minorUnitValue {
+			return nil, errors.New("down payment must be between 0 and total")
+		}
+		downDue := plan.DownDue
+		if downDue.IsZero() {
+			downDue = plan.FirstDue
+		}
+		schedule = append(schedule, Installment{0, downDue, plan.DownPayment})
+		var err error
+		if financed, err = total.Minus(plan.DownPayment); err != nil {
+			return nil, err
+		}
+	}
+
+	//every installment is the truncated even share, one of them absorbs the whole remainder
+	share := financed.minorUnitValue / int64(plan.Count)
+	remainder := financed.minorUnitValue - share*int64(plan.Count)
+	absorbing := 0
+	if plan.Remainder == RemainderToLast {
+		absorbing = plan.Count - 1
+	}
+	for i := 0; i < plan.Count; i++ {
+		installment := Installment{i + 1, plan.dueDate(i), newZeroAmount(total.curreny)}
+		installment.Amount.setMinorUnitValue(share)
+		if i == absorbing {
+			installment.Amount.setMinorUnitValue(share + remainder)
+		}
+		schedule = append(schedule, installment)
+	}
+	return schedule, nil
+}
+
+//dueDate returns the due date of the i-th installment (starts from 0)
+func (plan InstallmentPlan) dueDate(i int) time.Time {
+	switch plan.Frequency {
+	case Weekly:
+		return plan.FirstDue.AddDate(0, 0, 7*i)
+	case BiWeekly:
+		return plan.FirstDue.AddDate(0, 0, 14*i)
+	}
+
+	first := plan.FirstDue
+	monthStart := time.Date(first.Year(), first.Month()+time.Month(i), 1, first.Hour(), first.Minute(), first.Second(), first.Nanosecond(), first.Location())
+	lastDay := monthStart.AddDate(0, 1, -1).Day()
+	day := first.Day()
+	if day > lastDay || (plan.EndOfMonth && first.AddDate(0, 0, 1).Month() != first.Month()) {
+		day = lastDay
+	}
+	return monthStart.AddDate(0, 0, day-1)
+}
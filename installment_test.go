@@ -0,0 +1,100 @@
+package currency
+
+import (
+	"testing"
+	"time"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+}
+
+func TestSplitAndAllocate(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "10")
+
+	//case 1:
+	parts, _ := usdAmount.Split(3)
+	if parts[0].String() != "USD 3.34" || parts[1].String() != "USD 3.33" || parts[2].String() != "USD 3.33" {
+		t.Errorf("%s Split(3) == %v, want [USD 3.34 USD 3.33 USD 3.33]", usdAmount.String(), parts)
+	}
+
+	//case 2:
+	negativeAmount, _ := Factory.NewAmountInBasicUnit("USD", "-0.05")
+	parts, _ = negativeAmount.Allocate(70, 0, 30)
+	if parts[0].String() != "USD -0.04" || parts[1].String() != "USD 0.00" || parts[2].String() != "USD -0.01" {
+		t.Errorf("%s Allocate(70, 0, 30) == %v, want [USD -0.04 USD 0.00 USD -0.01]", negativeAmount.String(), parts)
+	}
+
+	//case 3:
+	_, err := usdAmount.Allocate(0, 0)
+	if err == nil {
+		t.Errorf("%s Allocate(0, 0) should be return an error, but no error return", usdAmount.String())
+	}
+}
+
+func TestInstallmentSchedule(t *testing.T) {
+	total, _ := Factory.NewAmountInBasicUnit("USD", "100")
+	downPayment, _ := Factory.NewAmountInBasicUnit("USD", "25")
+
+	//case 1: monthly at end of month with down payment
+	plan := InstallmentPlan{
+		Count:       3,
+		FirstDue:    time.Date(2021, 1, 31, 0, 0, 0, 0, time.UTC),
+		EndOfMonth:  true,
+		Remainder:   RemainderToLast,
+		DownPayment: downPayment,
+	}
+	schedule, _ := plan.Schedule(total)
+	if len(schedule) != 4 || schedule[0].Number != 0 || !schedule[0].Amount.IsEquals(downPayment) {
+		t.Fatalf("Schedule() == %v, want down payment and 3 installments", schedule)
+	}
+	wantDues := []string{"2021-01-31", "2021-02-28", "2021-03-31"}
+	wantAmounts := []string{"USD 25.00", "USD 25.00", "USD 25.00"}
+	for i, installment := range schedule[1:] {
+		if installment.Due.Format("2006-01-02") != wantDues[i] || installment.Amount.String() != wantAmounts[i] {
+			t.Errorf("Schedule()[%d] == %s %s, want %s %s", i+1, installment.Due.Format("2006-01-02"), installment.Amount.String(), wantDues[i], wantAmounts[i])
+		}
+	}
+
+	//case 2: monthly on 30th without end of month rule, remainder to last
+	plan = InstallmentPlan{Count: 3, FirstDue: time.Date(2021, 1, 30, 0, 0, 0, 0, time.UTC), Remainder: RemainderToLast}
+	schedule, _ = plan.Schedule(total)
+	if schedule[1].Due.Format("2006-01-02") != "2021-02-28" || schedule[2].Due.Format("2006-01-02") != "2021-03-30" || schedule[2].Amount.String() != "USD 33.34" {
+		t.Errorf("Schedule() == %v, want 2021-02-28, 2021-03-30 USD 33.34", schedule)
+	}
+
+	//case 3: the whole remainder is absorbed by the first or last installment
+	ten, _ := Factory.NewAmountInBasicUnit("USD", "10")
+	plan = InstallmentPlan{Count: 7, FirstDue: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	schedule, _ = plan.Schedule(ten)
+	if schedule[0].Amount.String() != "USD 1.48" || schedule[1].Amount.String() != "USD 1.42" || schedule[6].Amount.String() != "USD 1.42" {
+		t.Errorf("Schedule(%s) in 7 with remainder to first == %v, want USD 1.48, USD 1.42 x 6", ten.String(), schedule)
+	}
+	plan.Remainder = RemainderToLast
+	schedule, _ = plan.Schedule(ten)
+	if schedule[0].Amount.String() != "USD 1.42" || schedule[5].Amount.String() != "USD 1.42" || schedule[6].Amount.String() != "USD 1.48" {
+		t.Errorf("Schedule(%s) in 7 with remainder to last == %v, want USD 1.42 x 6, USD 1.48", ten.String(), schedule)
+	}
+
+	//case 4: bi-weekly
+	plan = InstallmentPlan{Count: 4, Frequency: BiWeekly, FirstDue: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)}
+	schedule, _ = plan.Schedule(total)
+	if schedule[3].Due.Format("2006-01-02") != "2021-02-12" {
+		t.Errorf("Schedule() last due == %s, want 2021-02-12", schedule[3].Due.Format("2006-01-02"))
+	}
+
+	//case 5:
+	plan.DownPayment = total
+	_, err := plan.Schedule(total)
+	if err == nil {
+		t.Errorf("Schedule() with down payment equal to total should be return an error, but no error return")
+	}
+
+	//case 6:
+	for _, plan := range []InstallmentPlan{{Count: 3, Frequency: InstallmentFrequency(3)}, {Count: 3, Remainder: RemainderPlacement(2)}} {
+		_, err = plan.Schedule(total)
+		if err == nil {
+			t.Errorf("Schedule() with frequency %d and remainder %d should be return an error, but no error return", plan.Frequency, plan.Remainder)
+		}
+	}
+}
@@ -0,0 +1,179 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+//DayCountConvention is the convention of counting the fraction of year between two dates
+type DayCountConvention uint8
+
+const (
+	//Act360 is actual days / 360
+	Act360 DayCountConvention = iota
+	//Act365Fixed is actual days / 365
+	Act365Fixed
+	//ActActISDA is actual days in leap years / 366 + actual days in other years / 365
+	ActActISDA
+	//Thirty360 is the 30/360 ISDA (bond basis) convention, each month has 30 days and each year has 360 days
+	Thirty360
+)
+
+//String returns the name of convention(e.g.: ACT/360)
+func (convention DayCountConvention) String() string {
+	switch convention {
+	case Act360:
+		return "ACT/360"
+	case Act365Fixed:
+		return "ACT/365F"
+	case ActActISDA:
+		return "ACT/ACT ISDA"
+	case Thirty360:
+		return "30/360"
+	}
+	return "unknown"
+}
+
+//YearFraction returns the exact fraction of year between two dates, the time of day is ignored
+//return error if end is before start, or convention is unknown
+func (convention DayCountConvention) YearFraction(start time.Time, end time.Time) (*big.Rat, error) {
+	start, end = truncateToDate(start), truncateToDate(end)
+	if end.Before(start) {
+		return nil, errors.New("end date can't be before start date")
+	}
+
+	switch convention {
+	case Act360:
+		return big.NewRat(daysBetween(start, end), 360), nil
+	case Act365Fixed:
+		return big.NewRat(daysBetween(start, end), 365), nil
+	case ActActISDA:
+		fraction := new(big.Rat)
+		for from := start; from.Before(end); {
+			nextYear := time.Date(from.Year()+1, time.January, 1, 0, 0, 0, 0, time.UTC)
+			to := end
+			if nextYear.Before(end) {
+				to = nextYear
+			}
+			fraction.Add(fraction, big.NewRat(daysBetween(from, to), daysInYear(from.Year())))
+			from = to
+		}
+		return fraction, nil
+	case Thirty360:
+		d1, d2 := start.Day(), end.Day()
+		if d1 == 31 {
+			d1 = 30
+		}
+		if d2 == 31 && d1 == 30 {
+			d2 = 30
+		}
+		days := 360*(end.Year()-start.Year()) + 30*(int(end.Month())-int(start.Month())) + (d2 - d1)
+		return big.NewRat(int64(days), 360), nil
+	}
+	return nil, errors.New("day count convention is unknown")
+}
+
+//SimpleInterest returns the simple interest accrued on amount between two dates, rounded by mode
+//annualRate is in percent (e.g.: 5 for 5% per year), taken as its shortest decimal representation
+//return error if end is before start, or annualRate is NaN or Inf
+func (amount Amount) SimpleInterest(annualRate float64, start time.Time, end time.Time, convention DayCountConvention, mode RoundingMode) (Amount, error) {
+	rate, err := ratFromFloat(annualRate)
+	if err != nil {
+		return Amount{}, err
+	}
+	fraction, err := convention.YearFraction(start, end)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	digits := amount.curreny.MinorUnitDigits()
+	interest := ratFromMinorUnit(amount.minorUnitValue, digits)
+	interest.Mul(interest, rate)
+	interest.Mul(interest, fraction)
+	interest.Quo(interest, big.NewRat(100, 1))
+	return amount.newRoundedAmount(interest, mode)
+}
+
+//CompoundInterest returns the compound interest accrued on amount between two dates, rounded by mode,
+//interest = amount * ((1 + annualRate/periodsPerYear) ^ (periodsPerYear * yearFraction) - 1)
+//annualRate is in percent (e.g.: 5 for 5% per year), taken as its shortest decimal representation
+//periodsPerYear is the compounding frequency (e.g.: 1 annually, 4 quarterly, 12 monthly, 365 daily)
+//return error if end is before start, annualRate is NaN or Inf, or periodsPerYear is not between 1 and 366
+//return error if the rate per period is not greater than -100%, or the compounding periods are more than 100 years of daily compounding
+func (amount Amount) CompoundInterest(annualRate float64, start time.Time, end time.Time, convention DayCountConvention, periodsPerYear int, mode RoundingMode) (Amount, error) {
+	if periodsPerYear <= 0 || periodsPerYear > maxPeriodsPerYear {
+		return Amount{}, fmt.Errorf("compounding periods per year must be between 1 and %d", maxPeriodsPerYear)
+	}
+	rate, err := ratFromFloat(annualRate)
+	if err != nil {
+		return Amount{}, err
+	}
+	fraction, err := convention.YearFraction(start, end)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	periodRate := rate.Quo(rate, big.NewRat(100*int64(periodsPerYear), 1))
+	base := periodRate.Add(periodRate, big.NewRat(1, 1)) //1 + annualRate/periodsPerYear
+	if base.Sign() <= 0 {
+		return Amount{}, errors.New("compounding rate per period must be greater than -100%")
+	}
+	periods := fraction.Mul(fraction, big.NewRat(int64(periodsPerYear), 1))
+	wholePeriods := new(big.Int).Quo(periods.Num(), periods.Denom())
+	if !wholePeriods.IsInt64() || wholePeriods.Int64() > maxCompoundingPeriods {
+		return Amount{}, errors.New("compounding periods are out of range")
+	}
+	//the whole periods are compounded exactly, the partial period in float64
+	growth := ratPow(base, int(wholePeriods.Int64()))
+	partial, _ := new(big.Rat).Sub(periods, new(big.Rat).SetInt(wholePeriods)).Float64()
+	if partial > 0 {
+		baseFloat, _ := base.Float64()
+		growth.Mul(growth, new(big.Rat).SetFloat64(math.Pow(baseFloat, partial)))
+	}
+
+	digits := amount.curreny.MinorUnitDigits()
+	interest := ratFromMinorUnit(amount.minorUnitValue, digits)
+	interest.Mul(interest, growth.Sub(growth, big.NewRat(1, 1)))
+	return amount.newRoundedAmount(interest, mode)
+}
+
+const (
+	//maxPeriodsPerYear is the max compounding frequency of CompoundInterest, daily in leap years
+	maxPeriodsPerYear = 366
+	//maxCompoundingPeriods is the max compounding periods of CompoundInterest, 100 years of daily compounding,
+	//the whole periods are compounded by exact rational power whose size grows with them
+	maxCompoundingPeriods = 100 * maxPeriodsPerYear
+)
+
+//newRoundedAmount returns an amount in the currency of amount, whose value is the exact value in basic unit rounded by mode
+//return error if the result overflows
+func (amount Amount) newRoundedAmount(value *big.Rat, mode RoundingMode) (Amount, error) {
+	minorUnitValue, err := roundRatToMinorUnit(value, amount.curreny.MinorUnitDigits(), mode)
+	if err != nil {
+		return Amount{}, err
+	}
+	result := newZeroAmount(amount.curreny)
+	result.setMinorUnitValue(minorUnitValue)
+	return result, nil
+}
+
+//truncateToDate returns the midnight in UTC of the date of t
+func truncateToDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+//daysBetween returns the actual days between two dates at midnight in UTC
+func daysBetween(start time.Time, end time.Time) int64 {
+	return int64(end.Sub(start) / (24 * time.Hour))
+}
+
+//daysInYear returns 366 for leap years, otherwise 365
+func daysInYear(year int) int64 {
+	if year%4 == 0 && (year%100 != 0 || year%400 == 0) {
+		return 366
+	}
+	return 365
+}
@@ -0,0 +1,92 @@
+package currency
+
+import (
+	"math"
+	"math/big"
+	"testing"
+	"time"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestYearFraction(t *testing.T) {
+	cases := []struct {
+		convention DayCountConvention
+		start      time.Time
+		end        time.Time
+		want       *big.Rat
+	}{
+		{Act360, date(2020, 1, 1), date(2020, 7, 1), big.NewRat(182, 360)},
+		{Act365Fixed, date(2020, 1, 1), date(2021, 1, 1), big.NewRat(366, 365)},
+		{ActActISDA, date(2019, 7, 1), date(2020, 7, 1), new(big.Rat).Add(big.NewRat(184, 365), big.NewRat(182, 366))},
+		{Thirty360, date(2020, 1, 31), date(2020, 3, 31), big.NewRat(60, 360)},
+		{Thirty360, date(2020, 2, 29), date(2021, 2, 28), big.NewRat(359, 360)},
+	}
+	for _, c := range cases {
+		got, _ := c.convention.YearFraction(c.start, c.end)
+		if got.Cmp(c.want) != 0 {
+			t.Errorf("%s YearFraction(%s, %s) == %s, want %s", c.convention, c.start.Format("2006-01-02"), c.end.Format("2006-01-02"), got, c.want)
+		}
+	}
+
+	_, err := Act360.YearFraction(date(2020, 1, 2), date(2020, 1, 1))
+	if err == nil {
+		t.Errorf("YearFraction() with end before start should be return an error, but no error return")
+	}
+}
+
+func TestSimpleInterest(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "1000")
+	got, _ := usdAmount.SimpleInterest(5, date(2020, 1, 1), date(2020, 12, 26), Act360, RoundHalfEven)
+	if got.String() != "USD 50.00" {
+		t.Errorf("%s SimpleInterest(5%%, 360 days, ACT/360) == %s, want USD 50.00", usdAmount.String(), got.String())
+	}
+}
+
+func TestCompoundInterest(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "1000")
+
+	//case 1:
+	got, _ := usdAmount.CompoundInterest(12, date(2020, 1, 1), date(2021, 1, 1), Thirty360, 12, RoundHalfEven)
+	if got.String() != "USD 126.83" {
+		t.Errorf("%s CompoundInterest(12%%, 1 year, monthly) == %s, want USD 126.83", usdAmount.String(), got.String())
+	}
+
+	//case 2: partial period
+	got, _ = usdAmount.CompoundInterest(10, date(2020, 1, 1), date(2020, 7, 1), Thirty360, 1, RoundHalfEven)
+	if got.String() != "USD 48.81" {
+		t.Errorf("%s CompoundInterest(10%%, half year, annually) == %s, want USD 48.81", usdAmount.String(), got.String())
+	}
+
+	//case 3:
+	_, err := usdAmount.CompoundInterest(10, date(2020, 1, 1), date(2020, 7, 1), Thirty360, 0, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s CompoundInterest() with 0 periods per year should be return an error, but no error return", usdAmount.String())
+	}
+	_, err = usdAmount.CompoundInterest(10, date(2020, 1, 1), date(2020, 7, 1), Thirty360, math.MaxInt32, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s CompoundInterest() with MaxInt32 periods per year should be return an error, but no error return", usdAmount.String())
+	}
+
+	//case 4: the base of partial period is negative
+	_, err = usdAmount.CompoundInterest(-200, date(2020, 1, 1), date(2020, 7, 1), Thirty360, 1, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s CompoundInterest(-200%%, annually) should be return an error, but no error return", usdAmount.String())
+	}
+
+	//case 5: the max periods are 100 years of daily compounding
+	got, err = usdAmount.CompoundInterest(5, date(2000, 1, 1), date(2010, 1, 1), Thirty360, 366, RoundHalfEven)
+	if err != nil || got.String() != "USD 648.66" {
+		t.Errorf("%s CompoundInterest(5%%, 10 years, 366 per year) == %s %v, want USD 648.66", usdAmount.String(), got.String(), err)
+	}
+	_, err = usdAmount.CompoundInterest(5, date(2000, 1, 1), date(2100, 1, 2), Thirty360, 366, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s CompoundInterest(5%%, over 100 years, 366 per year) should be return an error, but no error return", usdAmount.String())
+	}
+}
@@ -0,0 +1,338 @@
+//Package ledger is a double-entry ledger built on currency amounts
+package ledger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+var (
+	//ErrAccountExists is returned when opening an account with an existing id
+	ErrAccountExists = errors.New("account already exists")
+	//ErrAccountNotFound is returned when an account is not found
+	ErrAccountNotFound = errors.New("account is not found")
+	//ErrDuplicateEntry is returned by Storage when saving an entry with an existing idempotency key
+	ErrDuplicateEntry = errors.New("entry with same idempotency key already exists")
+	//ErrIdempotencyConflict is returned when posting an entry with a posted idempotency key but different content
+	ErrIdempotencyConflict = errors.New("idempotency key is used by a different entry")
+	//ErrUnbalancedEntry is returned when the debits and credits of an entry are not equal in any currency
+	ErrUnbalancedEntry = errors.New("debits and credits of entry are not balanced")
+)
+
+//Side is the side of a leg, debit or credit
+type Side uint8
+
+const (
+	//Debit increases the balance of account
+	Debit Side = iota
+	//Credit decreases the balance of account
+	Credit
+)
+
+//String returns the name of side
+func (side Side) String() string {
+	switch side {
+	case Debit:
+		return "DEBIT"
+	case Credit:
+		return "CREDIT"
+	default:
+		return fmt.Sprintf("Side(%d)", uint8(side))
+	}
+}
+
+//Account is a ledger account, all legs of an account are in its currency
+type Account struct {
+	ID       string
+	Currency string //ISO 4217 three-letter alphabetic code
+	Name     string
+}
+
+//Leg is a debit or credit of an account in a journal entry
+type Leg struct {
+	AccountID string
+	Side      Side
+	Amount    currency.Amount //must be greater than 0
+}
+
+//Entry is a journal entry of balanced legs
+type Entry struct {
+	ID             string //assigned by storage when posting
+	IdempotencyKey string //optional, posting the same entry with a posted key returns the posted entry
+	Time           time.Time
+	Description    string
+	Legs           []Leg
+}
+
+//Ledger is a double-entry ledger
+type Ledger struct {
+	storage Storage
+	locker  *sync.Mutex //serializes posting
+}
+
+//New create a new ledger with storage
+func New(storage Storage) *Ledger {
+	return &Ledger{storage: storage, locker: new(sync.Mutex)}
+}
+
+//OpenAccount create a new account
+//return error if the id is empty or exists
+//return error if currencyCode is not managed by factory
+func (ledger *Ledger) OpenAccount(id string, currencyCode string, name string) (Account, error) {
+	id = strings.TrimSpace(id)
+	if id == "" {
+		return Account{}, errors.New("account id can't be empty")
+	}
+	ccy, err := currency.Factory.GetCurrencyByCode(currencyCode)
+	if err != nil {
+		return Account{}, err
+	}
+
+	account := Account{ID: id, Currency: ccy.Code(), Name: name}
+	if err := ledger.storage.SaveAccount(account); err != nil {
+		return Account{}, err
+	}
+	return account, nil
+}
+
+//Post validate and post a journal entry, returns the posted entry with the id assigned by storage.
+//If the idempotency key of entry was posted, the posted entry is returned and nothing is changed
+//return ErrIdempotencyConflict error if the idempotency key was posted with different description, time or legs
+//return error if entry has less than 2 legs, or any leg's account is not found
+//return error if any leg's amount is not greater than 0, or its currency is different from account's
+//return error if any leg's side is neither Debit nor Credit
+//return error if debits and credits are not equal in any currency
+func (ledger *Ledger) Post(entry Entry) (Entry, error) {
+	ledger.locker.Lock()
+	defer ledger.locker.Unlock()
+
+	if entry.IdempotencyKey != "" {
+		posted, exists, err := ledger.storage.EntryByIdempotencyKey(entry.IdempotencyKey)
+		if err != nil {
+			return Entry{}, err
+		}
+		if exists {
+			return idempotentEntry(posted, entry)
+		}
+	}
+
+	if err := ledger.validate(entry); err != nil {
+		return Entry{}, err
+	}
+
+	request := entry
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	entry.Legs = append([]Leg(nil), entry.Legs...)
+	saved, err := ledger.storage.SaveEntry(entry)
+	if errors.Is(err, ErrDuplicateEntry) { //posted through another ledger sharing the storage
+		posted, _, err := ledger.storage.EntryByIdempotencyKey(entry.IdempotencyKey)
+		if err != nil {
+			return Entry{}, err
+		}
+		return idempotentEntry(posted, request)
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	return saved, nil
+}
+
+//idempotentEntry returns the posted entry of the idempotency key of request
+//return ErrIdempotencyConflict error if request has different description, time or legs,
+//the time of request is not compared if it's zero
+func idempotentEntry(posted Entry, request Entry) (Entry, error) {
+	same := posted.Description == request.Description && len(posted.Legs) == len(request.Legs) &&
+		(request.Time.IsZero() || posted.Time.Equal(request.Time))
+	for i := 0; same && i < len(posted.Legs); i++ {
+		postedLeg, requestLeg := posted.Legs[i], request.Legs[i]
+		same = postedLeg.AccountID == requestLeg.AccountID && postedLeg.Side == requestLeg.Side && postedLeg.Amount.IsEquals(requestLeg.Amount)
+	}
+	if !same {
+		return Entry{}, fmt.Errorf("%w: %s", ErrIdempotencyConflict, request.IdempotencyKey)
+	}
+	return posted, nil
+}
+
+//validate check the legs of entry are valid and balanced per currency
+func (ledger *Ledger) validate(entry Entry) error {
+	if len(entry.Legs) < 2 {
+		return errors.New("entry must have at least 2 legs")
+	}
+
+	imbalance, _ := currency.NewWallet()
+	for i, leg := range entry.Legs {
+		account, exists, err := ledger.storage.Account(leg.AccountID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("leg %d: %w: %s", i, ErrAccountNotFound, leg.AccountID)
+		}
+		if leg.Amount.CurrencyCode() != account.Currency {
+			return fmt.Errorf("leg %d: amount currency %s is different from account %s currency %s", i, leg.Amount.CurrencyCode(), account.ID, account.Currency)
+		}
+		if leg.Amount.MinorUnitValue() <= 0 {
+			return fmt.Errorf("leg %d: amount must be greater than 0", i)
+		}
+		if leg.Side != Debit && leg.Side != Credit {
+			return fmt.Errorf("leg %d: side %s is unknown", i, leg.Side)
+		}
+
+		if leg.Side == Debit {
+			err = imbalance.Add(leg.Amount)
+		} else {
+			err = imbalance.Subtract(leg.Amount)
+		}
+		if err != nil {
+			return fmt.Errorf("leg %d: %w", i, err)
+		}
+	}
+	if !imbalance.IsEmpty() {
+		return fmt.Errorf("%w: %s", ErrUnbalancedEntry, imbalance.String())
+	}
+	return nil
+}
+
+//Balance returns the balance of account, debits minus credits
+//return error if account is not found
+func (ledger *Ledger) Balance(accountID string) (currency.Amount, error) {
+	lines, err := ledger.RunningBalances(accountID)
+	if err != nil {
+		return currency.Amount{}, err
+	}
+	if len(lines) == 0 {
+		account, _, _ := ledger.storage.Account(accountID)
+		return currency.Factory.NewAmountInMinorUnit(account.Currency, 0)
+	}
+	return lines[len(lines)-1].Balance, nil
+}
+
+//BalanceLine is a leg of account with the balance after it
+type BalanceLine struct {
+	EntryID     string
+	Time        time.Time
+	Description string
+	Side        Side
+	Amount      currency.Amount
+	Balance     currency.Amount //debits minus credits after the leg
+}
+
+//RunningBalances returns the legs of account in the order of posting with running balances
+//return error if account is not found
+func (ledger *Ledger) RunningBalances(accountID string) ([]BalanceLine, error) {
+	account, exists, err := ledger.storage.Account(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, accountID)
+	}
+	entries, err := ledger.storage.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	balance, err := currency.Factory.NewAmountInMinorUnit(account.Currency, 0)
+	if err != nil {
+		return nil, err
+	}
+	var lines []BalanceLine
+	for _, entry := range entries {
+		for _, leg := range entry.Legs {
+			if leg.AccountID != account.ID {
+				continue
+			}
+			if leg.Side == Debit {
+				balance, err = balance.Add(leg.Amount)
+			} else {
+				balance, err = balance.Minus(leg.Amount)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("entry %s: %w", entry.ID, err)
+			}
+			lines = append(lines, BalanceLine{entry.ID, entry.Time, entry.Description, leg.Side, leg.Amount, balance})
+		}
+	}
+	return lines, nil
+}
+
+//TrialBalanceLine is the debit and credit totals of an account
+type TrialBalanceLine struct {
+	Account Account
+	Debit   currency.Amount
+	Credit  currency.Amount
+	Balance currency.Amount //debits minus credits
+}
+
+//TrialBalance is the report of totals of all accounts
+type TrialBalance struct {
+	Lines    []TrialBalanceLine
+	Debits   *currency.Wallet //total debits per currency
+	Credits  *currency.Wallet //total credits per currency
+	Balanced bool             //true if total debits equal total credits in every currency
+}
+
+//TrialBalance returns the trial balance report of all accounts
+func (ledger *Ledger) TrialBalance() (TrialBalance, error) {
+	accounts, err := ledger.storage.Accounts()
+	if err != nil {
+		return TrialBalance{}, err
+	}
+	entries, err := ledger.storage.Entries()
+	if err != nil {
+		return TrialBalance{}, err
+	}
+
+	debits, _ := currency.NewWallet()
+	credits, _ := currency.NewWallet()
+	accountDebits := make(map[string]*currency.Wallet)
+	accountCredits := make(map[string]*currency.Wallet)
+	for _, account := range accounts {
+		accountDebits[account.ID], _ = currency.NewWallet()
+		accountCredits[account.ID], _ = currency.NewWallet()
+	}
+	for _, entry := range entries {
+		for _, leg := range entry.Legs {
+			total, accountTotal := credits, accountCredits[leg.AccountID]
+			if leg.Side == Debit {
+				total, accountTotal = debits, accountDebits[leg.AccountID]
+			}
+			if err := total.Add(leg.Amount); err != nil {
+				return TrialBalance{}, fmt.Errorf("entry %s: %w", entry.ID, err)
+			}
+			if err := accountTotal.Add(leg.Amount); err != nil {
+				return TrialBalance{}, fmt.Errorf("entry %s: %w", entry.ID, err)
+			}
+		}
+	}
+
+	report := TrialBalance{Debits: debits, Credits: credits}
+	for _, account := range accounts {
+		debit, err := accountDebits[account.ID].Balance(account.Currency)
+		if err != nil {
+			return TrialBalance{}, err
+		}
+		credit, _ := accountCredits[account.ID].Balance(account.Currency)
+		balance, err := debit.Minus(credit)
+		if err != nil {
+			return TrialBalance{}, fmt.Errorf("account %s: %w", account.ID, err)
+		}
+		report.Lines = append(report.Lines, TrialBalanceLine{account, debit, credit, balance})
+	}
+	difference, _ := currency.NewWallet()
+	if err := difference.AddWallet(debits); err != nil {
+		return TrialBalance{}, err
+	}
+	if err := difference.SubtractWallet(credits); err != nil {
+		return TrialBalance{}, err
+	}
+	report.Balanced = difference.IsEmpty()
+	return report, nil
+}
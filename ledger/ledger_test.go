@@ -0,0 +1,174 @@
+package ledger
+
+import (
+	"errors"
+	"math"
+	"sync"
+	"testing"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+func init() {
+	currency.Factory.NewCurrency("USD", 2)
+	currency.Factory.NewCurrency("CNY", 2)
+}
+
+func newTestLedger() *Ledger {
+	ledger := New(NewMemoryStorage())
+	ledger.OpenAccount("cash", "USD", "Cash")
+	ledger.OpenAccount("revenue", "USD", "Revenue")
+	ledger.OpenAccount("cny-cash", "CNY", "CNY Cash")
+	return ledger
+}
+
+func usd(value string) currency.Amount {
+	amount, _ := currency.Factory.NewAmountInBasicUnit("USD", value)
+	return amount
+}
+
+func TestOpenAccount(t *testing.T) {
+	ledger := newTestLedger()
+
+	//case 1:
+	_, err := ledger.OpenAccount("cash", "USD", "Cash")
+	if !errors.Is(err, ErrAccountExists) {
+		t.Errorf("OpenAccount(\"cash\") == %v, want %v", err, ErrAccountExists)
+	}
+
+	//case 2:
+	_, err = ledger.OpenAccount("bank", "ABC", "Bank")
+	if err == nil {
+		t.Errorf("OpenAccount(\"bank\", \"ABC\") should be return an error, but no error return")
+	}
+}
+
+func TestPost(t *testing.T) {
+	ledger := newTestLedger()
+
+	//case 1: unbalanced
+	_, err := ledger.Post(Entry{Legs: []Leg{{"cash", Debit, usd("10")}, {"revenue", Credit, usd("9.99")}}})
+	if !errors.Is(err, ErrUnbalancedEntry) {
+		t.Errorf("Post(unbalanced entry) == %v, want %v", err, ErrUnbalancedEntry)
+	}
+
+	//case 2: currency different from account
+	cny, _ := currency.Factory.NewAmountInBasicUnit("CNY", "10")
+	_, err = ledger.Post(Entry{Legs: []Leg{{"cash", Debit, cny}, {"cny-cash", Credit, cny}}})
+	if err == nil {
+		t.Errorf("Post(entry with wrong currency) should be return an error, but no error return")
+	}
+
+	//case 3: idempotency
+	entry := Entry{IdempotencyKey: "order-1", Legs: []Leg{{"cash", Debit, usd("10")}, {"revenue", Credit, usd("10")}}}
+	first, _ := ledger.Post(entry)
+	second, _ := ledger.Post(entry)
+	balance, _ := ledger.Balance("cash")
+	if first.ID != second.ID || !balance.IsEquals(usd("10")) {
+		t.Errorf("Post() twice == %s %s with balance %s, want same id with balance USD 10.00", first.ID, second.ID, balance.String())
+	}
+
+	//case 4: same idempotency key with different legs
+	changed := Entry{IdempotencyKey: "order-1", Legs: []Leg{{"cash", Debit, usd("20")}, {"revenue", Credit, usd("20")}}}
+	_, err = ledger.Post(changed)
+	if !errors.Is(err, ErrIdempotencyConflict) {
+		t.Errorf("Post() with posted key and different legs == %v, want %v", err, ErrIdempotencyConflict)
+	}
+
+	//case 5: unknown side
+	_, err = ledger.Post(Entry{Legs: []Leg{{"cash", Debit, usd("10")}, {"revenue", Side(5), usd("10")}}})
+	if err == nil || Side(5).String() != "Side(5)" {
+		t.Errorf("Post(entry with Side(5) leg) == %v %s, want an error and Side(5)", err, Side(5).String())
+	}
+
+	//case 6: the posted history can't be changed through returned entries
+	posted, _ := ledger.Post(Entry{Legs: []Leg{{"cash", Debit, usd("1")}, {"revenue", Credit, usd("1")}}})
+	posted.Legs[0].Side = Credit
+	entries, _ := ledger.storage.Entries()
+	entries[0].Legs[0].Amount = usd("1000")
+	replayed, _ := ledger.Post(entry)
+	replayed.Legs[0].Side = Credit
+	balance, _ = ledger.Balance("cash")
+	if !balance.IsEquals(usd("11")) {
+		t.Errorf("Balance(\"cash\") after changing returned entries == %s, want USD 11.00", balance.String())
+	}
+}
+
+func TestPostSharedStorage(t *testing.T) {
+	storage := NewMemoryStorage()
+	ledger1, ledger2 := New(storage), New(storage)
+	ledger1.OpenAccount("cash", "USD", "Cash")
+	ledger1.OpenAccount("revenue", "USD", "Revenue")
+	entry := Entry{Legs: []Leg{{"cash", Debit, usd("10")}, {"revenue", Credit, usd("10")}}}
+
+	//case 1: the ids are unique across ledgers posting concurrently
+	var wait sync.WaitGroup
+	ids := make([]string, 20)
+	for i := range ids {
+		wait.Add(1)
+		go func(i int) {
+			defer wait.Done()
+			posting := ledger1
+			if i%2 == 1 {
+				posting = ledger2
+			}
+			posted, _ := posting.Post(entry)
+			ids[i] = posted.ID
+		}(i)
+	}
+	wait.Wait()
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			t.Fatalf("Post() through ledgers sharing storage == ids %v, want unique ids", ids)
+		}
+		seen[id] = true
+	}
+}
+
+func TestRunningBalancesAndTrialBalance(t *testing.T) {
+	ledger := newTestLedger()
+	ledger.Post(Entry{Legs: []Leg{{"cash", Debit, usd("10")}, {"revenue", Credit, usd("10")}}})
+	ledger.Post(Entry{Legs: []Leg{{"revenue", Debit, usd("2.5")}, {"cash", Credit, usd("2.5")}}})
+
+	//case 1:
+	lines, _ := ledger.RunningBalances("cash")
+	if len(lines) != 2 || !lines[0].Balance.IsEquals(usd("10")) || !lines[1].Balance.IsEquals(usd("7.5")) {
+		t.Errorf("RunningBalances(\"cash\") == %v, want balances USD 10.00, USD 7.50", lines)
+	}
+
+	//case 2:
+	report, _ := ledger.TrialBalance()
+	if !report.Balanced || len(report.Lines) != 3 {
+		t.Errorf("TrialBalance() == %v, want balanced with 3 lines", report)
+	}
+	revenue := report.Lines[1]
+	if !revenue.Debit.IsEquals(usd("2.5")) || !revenue.Credit.IsEquals(usd("10")) || !revenue.Balance.IsEquals(usd("-7.5")) {
+		t.Errorf("TrialBalance() revenue == %v, want debit USD 2.50, credit USD 10.00, balance USD -7.50", revenue)
+	}
+
+	//case 3:
+	_, err := ledger.RunningBalances("bank")
+	if !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("RunningBalances(\"bank\") == %v, want %v", err, ErrAccountNotFound)
+	}
+}
+
+func TestBalanceOverflow(t *testing.T) {
+	ledger := newTestLedger()
+	max, _ := currency.Factory.NewAmountInMinorUnit("USD", math.MaxInt64)
+	ledger.Post(Entry{Legs: []Leg{{"cash", Debit, max}, {"revenue", Credit, max}}})
+	ledger.Post(Entry{Legs: []Leg{{"cash", Debit, usd("1")}, {"revenue", Credit, usd("1")}}})
+
+	//case 1:
+	_, err := ledger.Balance("cash")
+	if err == nil {
+		t.Errorf("Balance(\"cash\") overflows should be return an error, but no error return")
+	}
+
+	//case 2:
+	_, err = ledger.TrialBalance()
+	if err == nil {
+		t.Errorf("TrialBalance() overflows should be return an error, but no error return")
+	}
+}
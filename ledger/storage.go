@@ -0,0 +1,115 @@
+package ledger
+
+import (
+	"strconv"
+	"sync"
+)
+
+//Storage is the pluggable storage of ledger, implementations must be safe for concurrent use
+type Storage interface {
+	//SaveAccount save a new account
+	SaveAccount(account Account) error
+	//Account returns the account by id, false if not found
+	Account(id string) (Account, bool, error)
+	//Accounts returns all accounts in the order of saving
+	Accounts() ([]Account, error)
+	//SaveEntry save a posted journal entry and returns it with a new id, the id is unique in the storage
+	//and must be assigned atomically with saving, so that ledgers sharing the storage never get duplicate ids
+	//return ErrDuplicateEntry error if the idempotency key of entry exists
+	SaveEntry(entry Entry) (Entry, error)
+	//EntryByIdempotencyKey returns the posted journal entry by idempotency key, false if not found
+	EntryByIdempotencyKey(key string) (Entry, bool, error)
+	//Entries returns all posted journal entries in the order of posting
+	Entries() ([]Entry, error)
+}
+
+//MemoryStorage is an in-memory Storage
+type MemoryStorage struct {
+	accounts     []Account
+	accountIndex map[string]int //key is account id, value is index of accounts
+	entries      []Entry
+	keyIndex     map[string]int //key is idempotency key, value is index of entries
+	locker       *sync.RWMutex
+}
+
+//NewMemoryStorage create a new in-memory storage
+func NewMemoryStorage() *MemoryStorage {
+	return &MemoryStorage{
+		accountIndex: make(map[string]int),
+		keyIndex:     make(map[string]int),
+		locker:       new(sync.RWMutex),
+	}
+}
+
+//SaveAccount implements Storage
+func (storage *MemoryStorage) SaveAccount(account Account) error {
+	storage.locker.Lock()
+	defer storage.locker.Unlock()
+	if _, exists := storage.accountIndex[account.ID]; exists {
+		return ErrAccountExists
+	}
+	storage.accountIndex[account.ID] = len(storage.accounts)
+	storage.accounts = append(storage.accounts, account)
+	return nil
+}
+
+//Account implements Storage
+func (storage *MemoryStorage) Account(id string) (Account, bool, error) {
+	storage.locker.RLock()
+	defer storage.locker.RUnlock()
+	i, exists := storage.accountIndex[id]
+	if !exists {
+		return Account{}, false, nil
+	}
+	return storage.accounts[i], true, nil
+}
+
+//Accounts implements Storage
+func (storage *MemoryStorage) Accounts() ([]Account, error) {
+	storage.locker.RLock()
+	defer storage.locker.RUnlock()
+	return append([]Account(nil), storage.accounts...), nil
+}
+
+//SaveEntry implements Storage, the ids are sequence numbers starting from 1
+func (storage *MemoryStorage) SaveEntry(entry Entry) (Entry, error) {
+	storage.locker.Lock()
+	defer storage.locker.Unlock()
+	if entry.IdempotencyKey != "" {
+		if _, exists := storage.keyIndex[entry.IdempotencyKey]; exists {
+			return Entry{}, ErrDuplicateEntry
+		}
+		storage.keyIndex[entry.IdempotencyKey] = len(storage.entries)
+	}
+	entry.ID = strconv.Itoa(len(storage.entries) + 1)
+	storage.entries = append(storage.entries, copyEntry(entry))
+	return entry, nil
+}
+
+//EntryByIdempotencyKey implements Storage
+func (storage *MemoryStorage) EntryByIdempotencyKey(key string) (Entry, bool, error) {
+	storage.locker.RLock()
+	defer storage.locker.RUnlock()
+	i, exists := storage.keyIndex[key]
+	if !exists {
+		return Entry{}, false, nil
+	}
+	return copyEntry(storage.entries[i]), true, nil
+}
+
+//Entries implements Storage
+func (storage *MemoryStorage) Entries() ([]Entry, error) {
+	storage.locker.RLock()
+	defer storage.locker.RUnlock()
+	entries := make([]Entry, len(storage.entries))
+	for i, entry := range storage.entries {
+		entries[i] = copyEntry(entry)
+	}
+	return entries, nil
+}
+
+//copyEntry returns entry with a copy of its legs, so that the posted history can't be changed by callers
+func copyEntry(entry Entry) Entry {
+	entry.Legs = append([]Leg(nil), entry.Legs...)
+	return entry
+}
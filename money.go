@@ -0,0 +1,218 @@
+package currency
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+//CurrencyTag is a type standing for a currency at compile time, Code returns its ISO 4217 three-letter alphabetic code.
+//A tag of other currency is an empty struct type (e.g.: type KWD struct{}; func (KWD) Code() string { return "KWD" }),
+//the currency must be managed by factory
+type CurrencyTag interface {
+	Code() string
+}
+
+//USD is the currency tag of US Dollar
+type USD struct{}
+
+//Code returns USD
+func (USD) Code() string { return "USD" }
+
+//EUR is the currency tag of Euro
+type EUR struct{}
+
+//Code returns EUR
+func (EUR) Code() string { return "EUR" }
+
+//GBP is the currency tag of Pound Sterling
+type GBP struct{}
+
+//Code returns GBP
+func (GBP) Code() string { return "GBP" }
+
+//JPY is the currency tag of Yen
+type JPY struct{}
+
+//Code returns JPY
+func (JPY) Code() string { return "JPY" }
+
+//CNY is the currency tag of Yuan Renminbi
+type CNY struct{}
+
+//Code returns CNY
+func (CNY) Code() string { return "CNY" }
+
+//CHF is the currency tag of Swiss Franc
+type CHF struct{}
+
+//Code returns CHF
+func (CHF) Code() string { return "CHF" }
+
+//Money is an amount whose currency is the type parameter C, so that
+//amounts of different currencies are different types (e.g.: Money[USD] and Money[EUR]),
+//and mixing them in Add, Minus or comparison fails to compile.
+//The zero value of Money is zero amount of C
+type Money[C CurrencyTag] struct {
+	amount Amount
+}
+
+//NewMoney create a new money by using basic unit value (e.g.: NewMoney[USD]("1.50"))
+//return error if the currency of C is not managed by factory
+//return error if basicUnitValue is not a numberic value
+func NewMoney[C CurrencyTag](basicUnitValue string) (Money[C], error) {
+	var tag C
+	amount, err := Factory.NewAmountInBasicUnit(tag.Code(), basicUnitValue)
+	if err != nil {
+		return Money[C]{}, err
+	}
+	return Money[C]{amount}, nil
+}
+
+//NewMoneyInMinorUnit create a new money by using minor unit value (e.g.: NewMoneyInMinorUnit[USD](150))
+//return error if the currency of C is not managed by factory
+func NewMoneyInMinorUnit[C CurrencyTag](minorUnitValue int64) (Money[C], error) {
+	var tag C
+	amount, err := Factory.NewAmountInMinorUnit(tag.Code(), minorUnitValue)
+	if err != nil {
+		return Money[C]{}, err
+	}
+	return Money[C]{amount}, nil
+}
+
+//MoneyFromAmount create a new money from the dynamic amount
+//return error if the currency of amount is not the currency of C
+func MoneyFromAmount[C CurrencyTag](amount Amount) (Money[C], error) {
+	var tag C
+	if amount.CurrencyCode() != tag.Code() {
+		return Money[C]{}, errors.New("the currency of amount is not " + tag.Code())
+	}
+	return Money[C]{amount}, nil
+}
+
+//Fx foreign exchange money of From to money of To, 1 From = rate To
+//return error if the currency of To is not managed by factory
+//return error if rate=0
+func Fx[From CurrencyTag, To CurrencyTag](money Money[From], rate float64) (Money[To], error) {
+	var tag To
+	amount, err := money.Amount().Fx(tag.Code(), rate)
+	if err != nil {
+		return Money[To]{}, err
+	}
+	return Money[To]{amount}, nil
+}
+
+//FxByRateSource foreign exchange money of From to money of To by using the rate provided by source
+//return error if source can't provide the rate
+//return error if the currency of To is not managed by factory
+func FxByRateSource[From CurrencyTag, To CurrencyTag](money Money[From], source RateSource) (Money[To], error) {
+	var tag To
+	amount, err := money.Amount().FxByRateSource(tag.Code(), source)
+	if err != nil {
+		return Money[To]{}, err
+	}
+	return Money[To]{amount}, nil
+}
+
+//Amount returns the dynamic amount of money
+func (money Money[C]) Amount() Amount {
+	if money.amount.curreny.code != "" {
+		return money.amount
+	}
+	var tag C
+	currency, err := Factory.GetCurrencyByCode(tag.Code())
+	if err != nil {
+		currency = Currency{code: tag.Code()}
+	}
+	return newZeroAmount(currency)
+}
+
+//CurrencyCode returns the currency code of C
+func (money Money[C]) CurrencyCode() string {
+	var tag C
+	return tag.Code()
+}
+
+//BasicUnitValue returns the value of money in currency's basic unit
+func (money Money[C]) BasicUnitValue() string {
+	return money.Amount().BasicUnitValue()
+}
+
+//MinorUnitValue returns the value of money in currency's minor unit
+func (money Money[C]) MinorUnitValue() int64 {
+	return money.amount.minorUnitValue
+}
+
+//Add return (money + other)
+//return error if the result overflows
+func (money Money[C]) Add(other Money[C]) (Money[C], error) {
+	result, err := money.Amount().Add(other.Amount())
+	if err != nil {
+		return Money[C]{}, err
+	}
+	return Money[C]{result}, nil
+}
+
+//Minus return (money - other)
+//return error if the result overflows
+func (money Money[C]) Minus(other Money[C]) (Money[C], error) {
+	result, err := money.Amount().Minus(other.Amount())
+	if err != nil {
+		return Money[C]{}, err
+	}
+	return Money[C]{result}, nil
+}
+
+//Multiply return (money * factor)
+//the result out of range is saturated and the result of NaN is 0, like Amount.Multiply
+func (money Money[C]) Multiply(factor float64) Money[C] {
+	return Money[C]{money.Amount().Multiply(factor)}
+}
+
+//Divide return (money / factor)
+//return error if factor is 0
+func (money Money[C]) Divide(factor float64) (Money[C], error) {
+	result, err := money.Amount().Divide(factor)
+	if err != nil {
+		return Money[C]{}, err
+	}
+	return Money[C]{result}, nil
+}
+
+//IsEquals return true if the values are same, otherwise return false
+func (money Money[C]) IsEquals(other Money[C]) bool {
+	return money.amount.minorUnitValue == other.amount.minorUnitValue
+}
+
+//IsGreatThan return true if money > other, otherwise return false
+func (money Money[C]) IsGreatThan(other Money[C]) bool {
+	return money.amount.minorUnitValue > other.amount.minorUnitValue
+}
+
+//String returns default format string of money(e.g.: USD 1.00)
+func (money Money[C]) String() string {
+	return money.Amount().String()
+}
+
+//MarshalJSON implements json.Marshaler, in the same format as Amount
+func (money Money[C]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(money.Amount())
+}
+
+//UnmarshalJSON implements json.Unmarshaler
+//return error if the currency is not the currency of C
+//JSON null is a no-op, the money is not changed
+func (money *Money[C]) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	var amount Amount
+	if err := json.Unmarshal(data, &amount); err != nil {
+		return err
+	}
+	result, err := MoneyFromAmount[C](amount)
+	if err != nil {
+		return err
+	}
+	*money = result
+	return nil
+}
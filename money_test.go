@@ -0,0 +1,85 @@
+package currency
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("EUR", 2)
+	Factory.NewCurrency("JPY", 0)
+}
+
+func TestMoneyArithmetic(t *testing.T) {
+	usd1, _ := NewMoney[USD]("1.5")
+	usd2, _ := NewMoneyInMinorUnit[USD](25)
+
+	//case 1: Add(Money[EUR]) fails to compile
+	got, err := usd1.Add(usd2)
+	if err != nil || got.String() != "USD 1.75" || got.MinorUnitValue() != 175 {
+		t.Errorf("%s Add(%s) == %s %v, want USD 1.75", usd1.String(), usd2.String(), got.String(), err)
+	}
+
+	//case 2: zero value
+	var zero Money[USD]
+	got, err = zero.Minus(usd2)
+	if err != nil || got.String() != "USD -0.25" || zero.String() != "USD 0.00" {
+		t.Errorf("%s Minus(%s) == %s %v, want USD -0.25", zero.String(), usd2.String(), got.String(), err)
+	}
+
+	//case 3:
+	if !usd1.IsGreatThan(usd2) || usd1.IsEquals(usd2) {
+		t.Errorf("%s IsGreatThan(%s) == false, want true", usd1.String(), usd2.String())
+	}
+
+	//case 4:
+	_, err = usd1.Divide(0)
+	if err == nil {
+		t.Errorf("%s Divide(0) should be return an error, but no error return", usd1.String())
+	}
+
+	//case 5:
+	max, _ := NewMoneyInMinorUnit[USD](math.MaxInt64)
+	_, err = max.Add(usd2)
+	if err == nil {
+		t.Errorf("%s Add(%s) should be return an error, but no error return", max.String(), usd2.String())
+	}
+}
+
+func TestMoneyConversion(t *testing.T) {
+	usd, _ := NewMoney[USD]("10")
+
+	//case 1:
+	jpy, err := Fx[USD, JPY](usd, 150.255)
+	if err != nil || jpy.String() != "JPY 1503" {
+		t.Errorf("Fx[USD, JPY](%s, 150.255) == %s %v, want JPY 1503", usd.String(), jpy.String(), err)
+	}
+
+	//case 2:
+	amount, _ := Factory.NewAmountInBasicUnit("EUR", "2")
+	_, err = MoneyFromAmount[USD](amount)
+	if err == nil {
+		t.Errorf("MoneyFromAmount[USD](%s) should be return an error, but no error return", amount.String())
+	}
+	eur, err := MoneyFromAmount[EUR](amount)
+	if err != nil || !eur.Amount().IsEquals(amount) {
+		t.Errorf("MoneyFromAmount[EUR](%s) == %s %v, want %s", amount.String(), eur.String(), err, amount.String())
+	}
+
+	//case 3:
+	data, _ := json.Marshal(usd)
+	if err := json.Unmarshal(data, &eur); err == nil {
+		t.Errorf("json.Unmarshal(%s, *Money[EUR]) should be return an error, but no error return", data)
+	}
+	var decoded Money[USD]
+	if err := json.Unmarshal(data, &decoded); err != nil || !decoded.IsEquals(usd) {
+		t.Errorf("json.Unmarshal(%s, *Money[USD]) == %s %v, want %s", data, decoded.String(), err, usd.String())
+	}
+
+	//case 4:
+	if err := json.Unmarshal([]byte("null"), &decoded); err != nil || !decoded.IsEquals(usd) {
+		t.Errorf("json.Unmarshal(null, *Money[USD]) == %s %v, want %s unchanged", decoded.String(), err, usd.String())
+	}
+}
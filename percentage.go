@@ -0,0 +1,100 @@
+package currency
+
+import (
+	"errors"
+	"math/big"
+)
+
+//Adjustment is the result of applying a discount or markup to an amount
+type Adjustment struct {
+	Original   Amount   //the amount before adjustment
+	Adjustment Amount   //the rounded discount or markup, zero or of the same sign as original
+	Result     Amount   //original - adjustment for discount, original + adjustment for markup
+	Remainder  *big.Rat //the exact adjustment minus the rounded one, in basic unit
+}
+
+//Percent returns (amount * percent / 100) rounded by mode, the percent is taken as its shortest decimal representation
+//return error if percent is NaN or Inf, mode is invalid, or the result overflows
+func (amount Amount) Percent(percent float64, mode RoundingMode) (Amount, error) {
+	result, _, err := amount.multiplyByDecimal(percent, 100, mode)
+	return result, err
+}
+
+//BasisPoints returns (amount * basisPoints / 10000) rounded by mode, the basis points is taken as its shortest decimal representation
+//return error if basisPoints is NaN or Inf, mode is invalid, or the result overflows
+func (amount Amount) BasisPoints(basisPoints float64, mode RoundingMode) (Amount, error) {
+	result, _, err := amount.multiplyByDecimal(basisPoints, 10000, mode)
+	return result, err
+}
+
+//PercentageOf returns the exact percentage that amount is of other (e.g.: USD 1.00 PercentageOf USD 4.00 = 25)
+//return error if the currency of two amount are not same
+//return error if other is 0
+func (amount Amount) PercentageOf(other Amount) (*big.Rat, error) {
+	if amount.curreny.Code() != other.curreny.Code() {
+		return nil, errors.New("Amount percentage fail: curreny are not same")
+	}
+	if other.minorUnitValue == 0 {
+		return nil, errors.New("Amount percentage fail: other amount can not be 0")
+	}
+	ratio := new(big.Rat).SetFrac(big.NewInt(amount.minorUnitValue), big.NewInt(other.minorUnitValue))
+	return ratio.Mul(ratio, big.NewRat(100, 1)), nil
+}
+
+//ApplyDiscount returns amount minus percent of it, the discount is rounded by mode
+//return error if percent is negative
+//return error if percent is NaN or Inf, mode is invalid, or the result overflows
+func (amount Amount) ApplyDiscount(percent float64, mode RoundingMode) (Adjustment, error) {
+	if percent < 0 {
+		return Adjustment{}, errors.New("discount percent can't be negative")
+	}
+	discount, remainder, err := amount.multiplyByDecimal(percent, 100, mode)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	result, err := amount.Minus(discount)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	return Adjustment{amount, discount, result, remainder}, nil
+}
+
+//ApplyMarkup returns amount plus percent of it, the markup is rounded by mode
+//return error if percent is negative
+//return error if percent is NaN or Inf, mode is invalid, or the result overflows
+func (amount Amount) ApplyMarkup(percent float64, mode RoundingMode) (Adjustment, error) {
+	if percent < 0 {
+		return Adjustment{}, errors.New("markup percent can't be negative")
+	}
+	markup, remainder, err := amount.multiplyByDecimal(percent, 100, mode)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	result, err := amount.Add(markup)
+	if err != nil {
+		return Adjustment{}, err
+	}
+	return Adjustment{amount, markup, result, remainder}, nil
+}
+
+//multiplyByDecimal returns (amount * numerator / denominator) rounded by mode,
+//and the exact result minus the rounded one in basic unit
+func (amount Amount) multiplyByDecimal(numerator float64, denominator int64, mode RoundingMode) (Amount, *big.Rat, error) {
+	factor, err := ratFromFloat(numerator)
+	if err != nil {
+		return Amount{}, nil, err
+	}
+	digits := amount.curreny.MinorUnitDigits()
+	exact := ratFromMinorUnit(amount.minorUnitValue, digits)
+	exact.Mul(exact, factor)
+	exact.Quo(exact, big.NewRat(denominator, 1))
+	minorUnitValue, err := roundRatToMinorUnit(exact, digits, mode)
+	if err != nil {
+		return Amount{}, nil, err
+	}
+
+	result := newZeroAmount(amount.curreny)
+	result.setMinorUnitValue(minorUnitValue)
+	remainder := exact.Sub(exact, ratFromMinorUnit(minorUnitValue, digits))
+	return result, remainder, nil
+}
@@ -0,0 +1,78 @@
+package currency
+
+import (
+	"math/big"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+}
+
+func TestPercent(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "10.05")
+
+	//case 1:
+	got, _ := usdAmount.Percent(15, RoundHalfEven)
+	if got.String() != "USD 1.51" {
+		t.Errorf("%s Percent(15) == %s, want USD 1.51", usdAmount.String(), got.String())
+	}
+
+	//case 2:
+	got, _ = usdAmount.BasisPoints(250, RoundDown)
+	if got.String() != "USD 0.25" {
+		t.Errorf("%s BasisPoints(250) == %s, want USD 0.25", usdAmount.String(), got.String())
+	}
+}
+
+func TestPercentageOf(t *testing.T) {
+	usdAmount1, _ := Factory.NewAmountInBasicUnit("USD", "1")
+	usdAmount2, _ := Factory.NewAmountInBasicUnit("USD", "3")
+
+	//case 1:
+	got, _ := usdAmount1.PercentageOf(usdAmount2)
+	if want := big.NewRat(100, 3); got.Cmp(want) != 0 {
+		t.Errorf("%s PercentageOf(%s) == %s, want %s", usdAmount1.String(), usdAmount2.String(), got, want)
+	}
+
+	//case 2:
+	zeroAmount, _ := Factory.NewAmountInBasicUnit("USD", "0")
+	_, err := usdAmount1.PercentageOf(zeroAmount)
+	if err == nil {
+		t.Errorf("%s PercentageOf(%s) should be return an error, but no error return", usdAmount1.String(), zeroAmount.String())
+	}
+
+	//case 3:
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "1")
+	_, err = usdAmount1.PercentageOf(cnyAmount)
+	if err == nil {
+		t.Errorf("%s PercentageOf(%s) should be return an error, but no error return", usdAmount1.String(), cnyAmount.String())
+	}
+}
+
+func TestApplyDiscountAndMarkup(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "9.99")
+
+	//case 1:
+	adjustment, _ := usdAmount.ApplyDiscount(12.5, RoundHalfUp)
+	if adjustment.Adjustment.String() != "USD 1.25" || adjustment.Result.String() != "USD 8.74" || adjustment.Remainder.Cmp(big.NewRat(-1, 800)) != 0 {
+		t.Errorf("%s ApplyDiscount(12.5) == %s %s %s, want USD 1.25 USD 8.74 -1/800", usdAmount.String(), adjustment.Adjustment.String(), adjustment.Result.String(), adjustment.Remainder)
+	}
+
+	//case 2:
+	adjustment, _ = usdAmount.ApplyMarkup(10, RoundHalfEven)
+	if adjustment.Adjustment.String() != "USD 1.00" || adjustment.Result.String() != "USD 10.99" {
+		t.Errorf("%s ApplyMarkup(10) == %s %s, want USD 1.00 USD 10.99", usdAmount.String(), adjustment.Adjustment.String(), adjustment.Result.String())
+	}
+
+	//case 3:
+	_, err := usdAmount.ApplyDiscount(-10, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s ApplyDiscount(-10) should be return an error, but no error return", usdAmount.String())
+	}
+	_, err = usdAmount.ApplyMarkup(-10, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s ApplyMarkup(-10) should be return an error, but no error return", usdAmount.String())
+	}
+}
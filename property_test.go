@@ -0,0 +1,231 @@
+package currency
+
+import (
+	"encoding/json"
+	"math/big"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+	Factory.NewCurrency("JPY", 0)
+	Factory.NewCurrency("KWD", 3)
+}
+
+//propertyCurrencies are the currencies of property tests, with 0, 2 and 3 minor unit digits
+var propertyCurrencies = []string{"JPY", "USD", "KWD"}
+
+func TestAddMinusProperty(t *testing.T) {
+	property := func(a int64, b int64, currencyIndex uint8) bool {
+		code := propertyCurrencies[int(currencyIndex)%len(propertyCurrencies)]
+		amountA, _ := Factory.NewAmountInMinorUnit(code, a>>1) //a>>1 + b>>1 never overflows
+		amountB, _ := Factory.NewAmountInMinorUnit(code, b>>1)
+		sum, err := amountA.Add(amountB)
+		if err != nil {
+			return false
+		}
+		reversedSum, _ := amountB.Add(amountA)
+		difference, _ := sum.Minus(amountB)
+		return sum.IsEquals(reversedSum) && difference.IsEquals(amountA)
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Errorf("a+b == b+a and a+b-b == a: %v", err)
+	}
+}
+
+func TestAllocateProperty(t *testing.T) {
+	property := func(value int64, ratios []uint16) bool {
+		amount, _ := Factory.NewAmountInMinorUnit("USD", value)
+		int64Ratios := make([]int64, len(ratios))
+		allZero := true
+		for i, ratio := range ratios {
+			int64Ratios[i] = int64(ratio)
+			allZero = allZero && ratio == 0
+		}
+		parts, err := amount.Allocate(int64Ratios...)
+		if allZero {
+			return err != nil
+		}
+		sum, err := Sum(parts)
+		if err != nil || !sum.IsEquals(amount) {
+			return false
+		}
+		for i, part := range parts {
+			if ratios[i] == 0 && part.MinorUnitValue() != 0 {
+				return false
+			}
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Errorf("the parts of Allocate sum up to amount: %v", err)
+	}
+}
+
+func TestFxRoundTripProperty(t *testing.T) {
+	//1 target minor unit is worth no more than 1 source minor unit when rate >= 1 and digits are same
+	property := func(value int32, rateSeed uint32) bool {
+		rate := 1 + float64(rateSeed%1000000)/10000
+		amount, _ := Factory.NewAmountInMinorUnit("USD", int64(value))
+		converted, err := amount.Fx("CNY", rate)
+		if err != nil {
+			return false
+		}
+		back, err := converted.Fx("USD", 1/rate)
+		if err != nil {
+			return false
+		}
+		difference := back.MinorUnitValue() - amount.MinorUnitValue()
+		return difference >= -1 && difference <= 1
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Errorf("Fx with rate then inverse rate stays within one minor unit: %v", err)
+	}
+}
+
+func TestStringParseRoundTripProperty(t *testing.T) {
+	property := func(value int64, currencyIndex uint8) bool {
+		code := propertyCurrencies[int(currencyIndex)%len(propertyCurrencies)]
+		amount, _ := Factory.NewAmountInMinorUnit(code, value)
+		parsed, err := Factory.ParseAmount(amount.String())
+		if err != nil || !parsed.IsEquals(amount) {
+			return false
+		}
+		data, _ := json.Marshal(amount)
+		var decoded Amount
+		return json.Unmarshal(data, &decoded) == nil && decoded.IsEquals(amount)
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Errorf("ParseAmount(String()) and JSON round trip: %v", err)
+	}
+}
+
+func TestParseBasicUnitValueProperty(t *testing.T) {
+	//parseBasicUnitValue agrees with exact rational rounding in all rounding modes
+	property := func(integer uint32, fraction uint64, fractionDigits uint8, exponent int8, negative bool, mode uint8) bool {
+		text := strconv.FormatUint(uint64(integer), 10)
+		if fractionDigits%8 != 0 {
+			text += "." + strconv.FormatUint(fraction, 10)[:1+int(fractionDigits%8)%len(strconv.FormatUint(fraction, 10))]
+		}
+		if exponent%4 != 0 {
+			text += "e" + strconv.Itoa(int(exponent%8))
+		}
+		if negative {
+			text = "-" + text
+		}
+		roundingMode := RoundingMode(mode % uint8(len(roundingModeNames)))
+		got, _, err := parseBasicUnitValue(text, 2, roundingMode)
+		rat, _ := new(big.Rat).SetString(text)
+		want, wantErr := roundRatToMinorUnit(rat, 2, roundingMode)
+		if (err == nil) != (wantErr == nil) || got != want {
+			t.Logf("parseBasicUnitValue(%q, 2, %s) == %d %v, want %d %v", text, roundingMode, got, err, want, wantErr)
+			return false
+		}
+		return true
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Errorf("parseBasicUnitValue agrees with big.Rat: %v", err)
+	}
+}
+
+func TestParseBasicUnitValue(t *testing.T) {
+	cases := []struct {
+		text         string
+		mode         RoundingMode
+		want         int64
+		excessDigits int
+	}{
+		{"1.015", RoundHalfEven, 102, 1}, //float64 of 1.015 is below 1.015
+		{"2.675", RoundHalfEven, 268, 1},
+		{"1.005", RoundHalfEven, 100, 1},
+		{"-1.005", RoundHalfUp, -101, 1},
+		{"1.2300", RoundHalfEven, 123, 0},
+		{"-0.001", RoundFloor, -1, 1},
+		{"12345678901234567.89", RoundHalfEven, 1234567890123456789, 0}, //beyond float64 precision
+		{"-92233720368547758.08", RoundHalfEven, -9223372036854775808, 0},
+		{"1.5e3", RoundHalfEven, 150000, 0},
+		{"+.5", RoundHalfEven, 50, 0},
+		{"125e-5", RoundHalfEven, 0, 3},
+	}
+	for _, c := range cases {
+		got, excessDigits, err := parseBasicUnitValue(c.text, 2, c.mode)
+		if err != nil || got != c.want || excessDigits != c.excessDigits {
+			t.Errorf("parseBasicUnitValue(%q, 2, %s) == %d %d %v, want %d %d", c.text, c.mode, got, excessDigits, err, c.want, c.excessDigits)
+		}
+	}
+
+	for _, text := range []string{"", ".", "-", "1.2.3", "1e", "1e99999", "0x10", "Inf", "NaN", "1_000", "92233720368547758.08"} {
+		_, _, err := parseBasicUnitValue(text, 2, RoundHalfEven)
+		if err == nil {
+			t.Errorf("parseBasicUnitValue(%q, 2) should be return an error, but no error return", text)
+		}
+	}
+}
+
+func FuzzNewAmountInBasicUnit(f *testing.F) {
+	for _, seed := range []string{"1.015", "-0.005", "1e3", " 12345678901234567.89 ", "0x1p-2", "-92233720368547758.08", ".5", "1.", "NaN"} {
+		f.Add("USD", seed)
+	}
+	f.Add("JPY", "-1503.5")
+	f.Add("KWD", "0.0005")
+	f.Fuzz(func(t *testing.T, code string, text string) {
+		amount, err := Factory.NewAmountInBasicUnit(code, text)
+		if err != nil {
+			return
+		}
+
+		rat, ok := new(big.Rat).SetString(strings.TrimSpace(text))
+		if !ok {
+			t.Fatalf("NewAmountInBasicUnit(%q, %q) == %s, but it is not a decimal value", code, text, amount.String())
+		}
+		want, err := roundRatToMinorUnit(rat, amount.curreny.MinorUnitDigits(), RoundHalfEven)
+		if err != nil || want != amount.MinorUnitValue() {
+			t.Errorf("NewAmountInBasicUnit(%q, %q) == %d, want %d %v", code, text, amount.MinorUnitValue(), want, err)
+		}
+
+		parsed, err := Factory.ParseAmount(amount.String())
+		if err != nil || !parsed.IsEquals(amount) {
+			t.Errorf("ParseAmount(%q) == %s %v, want %s", amount.String(), parsed.String(), err, amount.String())
+		}
+	})
+}
+
+func FuzzParseAmount(f *testing.F) {
+	for _, seed := range []string{"USD 1.00", " usd  -1.5 ", "USD1.5", "JPY 1e3", "KWD 0.0005", "ABC 1"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, text string) {
+		amount, err := Factory.ParseAmount(text)
+		if err != nil {
+			return
+		}
+		parsed, err := Factory.ParseAmount(amount.String())
+		if err != nil || !parsed.IsEquals(amount) {
+			t.Errorf("ParseAmount(%q) == %s %v, want %s", amount.String(), parsed.String(), err, amount.String())
+		}
+	})
+}
+
+func FuzzAmountUnmarshalJSON(f *testing.F) {
+	for _, seed := range []string{`{"currency":"USD","value":"1.00"}`, `{"currency":"jpy","value":"-1e3"}`, `{"currency":"USD"}`, `null`, `[]`} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var amount Amount
+		if err := json.Unmarshal(data, &amount); err != nil || amount.CurrencyCode() == "" {
+			return //null leaves the zero amount, which has no currency to round trip
+		}
+		encoded, err := json.Marshal(amount)
+		if err != nil {
+			t.Fatalf("json.Marshal(%s) == %v, want nil", amount.String(), err)
+		}
+		var decoded Amount
+		if err := json.Unmarshal(encoded, &decoded); err != nil || !decoded.IsEquals(amount) {
+			t.Errorf("json.Unmarshal(%s) == %s %v, want %s", encoded, decoded.String(), err, amount.String())
+		}
+	})
+}
@@ -0,0 +1,108 @@
+package currency
+
+import (
+	"errors"
+	"math/big"
+	"time"
+)
+
+//ProrationUnit is the unit of measuring periods in proration
+type ProrationUnit uint8
+
+const (
+	//ProrateByDay measures periods in calendar days, the time of day is ignored
+	ProrateByDay ProrationUnit = iota
+	//ProrateBySecond measures periods in seconds
+	ProrateBySecond
+)
+
+//Period is a half-open time interval [Start, End)
+type Period struct {
+	Start time.Time
+	End   time.Time
+}
+
+//MonthPeriod returns the calendar month containing t, in the location of t
+func MonthPeriod(t time.Time) Period {
+	start := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	return Period{start, start.AddDate(0, 1, 0)}
+}
+
+//length returns the length of period in unit
+func (period Period) length(unit ProrationUnit) int64 {
+	if unit == ProrateBySecond {
+		return int64(period.End.Sub(period.Start) / time.Second)
+	}
+	return daysBetween(truncateToDate(period.Start), truncateToDate(period.End))
+}
+
+//contains return true if sub is inside period
+func (period Period) contains(sub Period) bool {
+	return !sub.Start.Before(period.Start) && !sub.End.After(period.End) && !sub.End.Before(sub.Start)
+}
+
+//Prorate returns the share of amount for sub-interval of period rounded by mode,
+//and the residual (exact share minus rounded share, in basic unit)
+//return error if period is empty, or sub-interval is not inside period
+func (amount Amount) Prorate(period Period, sub Period, unit ProrationUnit, mode RoundingMode) (Amount, *big.Rat, error) {
+	total := period.length(unit)
+	if total <= 0 {
+		return Amount{}, nil, errors.New("proration period can't be empty")
+	}
+	if !period.contains(sub) {
+		return Amount{}, nil, errors.New("proration sub-interval is not inside period")
+	}
+
+	exact := ratFromMinorUnit(amount.minorUnitValue, amount.curreny.MinorUnitDigits())
+	exact.Mul(exact, big.NewRat(sub.length(unit), total))
+	share, err := amount.newRoundedAmount(exact, mode)
+	if err != nil {
+		return Amount{}, nil, err
+	}
+	residual := exact.Sub(exact, ratFromMinorUnit(share.minorUnitValue, amount.curreny.MinorUnitDigits()))
+	return share, residual, nil
+}
+
+//ProrateSplit splits amount into the consecutive sub-intervals of period separated by boundaries,
+//the shares are rounded on cumulative lengths so that they sum up to amount exactly
+//return error if mode is invalid
+//return error if period is empty, or boundaries are not ascending inside period
+func (amount Amount) ProrateSplit(period Period, unit ProrationUnit, mode RoundingMode, boundaries ...time.Time) ([]Amount, error) {
+	if err := mode.validate(); err != nil {
+		return nil, err
+	}
+	total := period.length(unit)
+	if total <= 0 {
+		return nil, errors.New("proration period can't be empty")
+	}
+
+	points := append(append([]time.Time{period.Start}, boundaries...), period.End)
+	shares := make([]Amount, 0, len(points)-1)
+	var previous int64 //the rounded cumulative share of previous sub-intervals in minor unit
+	for i := 1; i < len(points); i++ {
+		if !period.contains(Period{points[i-1], points[i]}) {
+			return nil, errors.New("proration boundaries are not ascending inside period")
+		}
+		cumulative := big.NewRat(amount.minorUnitValue, 1)
+		cumulative.Mul(cumulative, big.NewRat(Period{period.Start, points[i]}.length(unit), total))
+		current := roundRat(cumulative, mode).Int64()
+
+		share := newZeroAmount(amount.curreny)
+		share.setMinorUnitValue(current - previous)
+		shares = append(shares, share)
+		previous = current
+	}
+	return shares, nil
+}
+
+//CreditUnused splits amount paid for period at the time of plan change,
+//returns the used amount of [period.Start, changeAt) rounded by mode, and the credit of unused time,
+//used + credit == amount exactly
+//return error if period is empty, or changeAt is not inside period
+func (amount Amount) CreditUnused(period Period, changeAt time.Time, unit ProrationUnit, mode RoundingMode) (Amount, Amount, error) {
+	shares, err := amount.ProrateSplit(period, unit, mode, changeAt)
+	if err != nil {
+		return Amount{}, Amount{}, err
+	}
+	return shares[0], shares[1], nil
+}
@@ -0,0 +1,65 @@
+package currency
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+}
+
+func TestMonthPeriod(t *testing.T) {
+	period := MonthPeriod(time.Date(2020, 2, 15, 10, 0, 0, 0, time.UTC))
+	if period.length(ProrateByDay) != 29 {
+		t.Errorf("MonthPeriod(2020-02-15) length == %d, want 29", period.length(ProrateByDay))
+	}
+}
+
+func TestProrate(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "10")
+	period := MonthPeriod(time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC))
+
+	//case 1:
+	sub := Period{time.Date(2021, 2, 15, 0, 0, 0, 0, time.UTC), period.End}
+	got, residual, _ := usdAmount.Prorate(period, sub, ProrateByDay, RoundHalfEven)
+	if got.String() != "USD 5.00" || residual.Sign() != 0 {
+		t.Errorf("%s Prorate(14 of 28 days) == %s %s, want USD 5.00 0", usdAmount.String(), got.String(), residual)
+	}
+
+	//case 2:
+	sub = Period{period.Start, time.Date(2021, 2, 4, 0, 0, 0, 0, time.UTC)}
+	got, residual, _ = usdAmount.Prorate(period, sub, ProrateByDay, RoundHalfEven)
+	if got.String() != "USD 1.07" || residual.Cmp(big.NewRat(1, 700)) != 0 {
+		t.Errorf("%s Prorate(3 of 28 days) == %s %s, want USD 1.07 1/700", usdAmount.String(), got.String(), residual)
+	}
+
+	//case 3:
+	_, _, err := usdAmount.Prorate(period, Period{period.Start, period.End.Add(time.Second)}, ProrateBySecond, RoundHalfEven)
+	if err == nil {
+		t.Errorf("%s Prorate() outside period should be return an error, but no error return", usdAmount.String())
+	}
+}
+
+func TestProrateSplit(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "10")
+	period := MonthPeriod(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	//case 1:
+	var boundaries []time.Time
+	for day := 2; day <= 31; day++ {
+		boundaries = append(boundaries, time.Date(2021, 1, day, 0, 0, 0, 0, time.UTC))
+	}
+	shares, _ := usdAmount.ProrateSplit(period, ProrateByDay, RoundHalfEven, boundaries...)
+	sum, _ := Sum(shares)
+	if len(shares) != 31 || !sum.IsEquals(usdAmount) {
+		t.Errorf("%s ProrateSplit(31 days) == %d shares sum %s, want 31 shares sum %s", usdAmount.String(), len(shares), sum.String(), usdAmount.String())
+	}
+
+	//case 2:
+	used, credit, _ := usdAmount.CreditUnused(period, time.Date(2021, 1, 11, 12, 0, 0, 0, time.UTC), ProrateBySecond, RoundHalfEven)
+	if used.String() != "USD 3.39" || credit.String() != "USD 6.61" {
+		t.Errorf("%s CreditUnused(10.5 of 31 days) == %s %s, want USD 3.39 USD 6.61", usdAmount.String(), used.String(), credit.String())
+	}
+}
@@ -0,0 +1,125 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+//Rate is a two-sided exchange rate of currency pair, 1 base currency = bid/ask quote currency
+type Rate struct {
+	base  string  //three-letter alphabetic code of base currency
+	quote string  //three-letter alphabetic code of quote currency
+	bid   float64 //the rate at which base currency is bought from customer
+	ask   float64 //the rate at which base currency is sold to customer
+}
+
+//NewRate create a new two-sided rate object
+//return error if currency code is not a three-letter alphabetic code
+//return error if bid is not greater than 0, or ask is not finite or less than bid
+func NewRate(baseCurrencyCode string, quoteCurrencyCode string, bid float64, ask float64) (Rate, error) {
+	baseCurrencyCode, quoteCurrencyCode, err := normalizeCurrencyPair(baseCurrencyCode, quoteCurrencyCode)
+	if err != nil {
+		return Rate{}, err
+	}
+	if baseCurrencyCode == quoteCurrencyCode {
+		return Rate{}, errors.New("the currencies of rate are same")
+	}
+	if !(bid > 0) {
+		return Rate{}, errors.New("bid rate must be greater than 0")
+	}
+	if math.IsInf(ask, 0) || !(ask >= bid) {
+		return Rate{}, errors.New("ask rate must be finite and not less than bid rate")
+	}
+	return Rate{baseCurrencyCode, quoteCurrencyCode, bid, ask}, nil
+}
+
+//Base returns the three-letter alphabetic code of base currency
+func (rate Rate) Base() string {
+	return rate.base
+}
+
+//Quote returns the three-letter alphabetic code of quote currency
+func (rate Rate) Quote() string {
+	return rate.quote
+}
+
+//Bid returns the rate at which base currency is bought from customer
+func (rate Rate) Bid() float64 {
+	return rate.bid
+}
+
+//Ask returns the rate at which base currency is sold to customer
+func (rate Rate) Ask() float64 {
+	return rate.ask
+}
+
+//Mid returns the middle rate of bid and ask, used for reporting
+func (rate Rate) Mid() float64 {
+	return (rate.bid + rate.ask) / 2
+}
+
+//Spread returns the difference between ask and bid
+func (rate Rate) Spread() float64 {
+	return rate.ask - rate.bid
+}
+
+//String returns default format string of rate(e.g.: USD/CNY 6.7800/6.8200)
+func (rate Rate) String() string {
+	return fmt.Sprintf("%s/%s %.4f/%.4f", rate.base, rate.quote, rate.bid, rate.ask)
+}
+
+//FxByRate foreign exchange by using the side of rate matching the direction of conversion,
+//base currency is converted at bid, quote currency is converted at ask,
+//markupBasisPoints widens the applied rate against customer (e.g: 50 means 0.5%).
+//Returns the converted amount and the spread against mid rate in target currency
+//return error if the currency of amount is neither base nor quote currency of rate
+//return error if markupBasisPoints is not in [0, 10000)
+func (amount Amount) FxByRate(rate Rate, markupBasisPoints float64) (Amount, Amount, error) {
+	if !(markupBasisPoints >= 0 && markupBasisPoints < 10000) {
+		return Amount{}, Amount{}, errors.New("markup basis points must be in [0, 10000)")
+	}
+	markup := markupBasisPoints / 10000
+
+	var targetCurrencyCode string
+	var appliedRate, midRate float64
+	switch amount.curreny.Code() {
+	case rate.base:
+		targetCurrencyCode = rate.quote
+		appliedRate = rate.bid * (1 - markup)
+		midRate = rate.Mid()
+	case rate.quote:
+		targetCurrencyCode = rate.base
+		appliedRate = 1 / (rate.ask * (1 + markup))
+		midRate = 1 / rate.Mid()
+	default:
+		return Amount{}, Amount{}, fmt.Errorf("curreny %s is not in rate %s/%s", amount.curreny.Code(), rate.base, rate.quote)
+	}
+
+	converted, err := amount.Fx(targetCurrencyCode, appliedRate)
+	if err != nil {
+		return Amount{}, Amount{}, err
+	}
+	midConverted, err := amount.Fx(targetCurrencyCode, midRate)
+	if err != nil {
+		return Amount{}, Amount{}, err
+	}
+	spread, err := midConverted.Minus(converted)
+	if err != nil {
+		return Amount{}, Amount{}, err
+	}
+	return converted, spread, nil
+}
+
+//FxByMidRate foreign exchange by using the mid rate, used for reporting
+//return error if the currency of amount is neither base nor quote currency of rate
+func (amount Amount) FxByMidRate(rate Rate) (Amount, error) {
+	switch amount.curreny.Code() {
+	case rate.base:
+		return amount.Fx(rate.quote, rate.Mid())
+	case rate.quote:
+		return amount.Fx(rate.base, 1/rate.Mid())
+	default:
+		return Amount{}, fmt.Errorf("curreny %s is not in rate %s/%s", amount.curreny.Code(), rate.base, rate.quote)
+	}
+}
@@ -0,0 +1,76 @@
+package currency
+
+import (
+	"math"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+}
+
+func TestNewRate(t *testing.T) {
+	//case 1:
+	_, err := NewRate("USD", "CNY", 0, 6.8)
+	if err == nil {
+		t.Errorf("NewRate(\"USD\", \"CNY\", 0, 6.8) should be return an error, but no error return")
+	}
+
+	//case 2:
+	_, err = NewRate("USD", "CNY", 6.8, 6.7)
+	if err == nil {
+		t.Errorf("NewRate(\"USD\", \"CNY\", 6.8, 6.7) should be return an error, but no error return")
+	}
+
+	//case 3:
+	rate, _ := NewRate("usd", "cny", 6.75, 6.85)
+	if math.Abs(rate.Mid()-6.8) > 1e-9 {
+		t.Errorf("%s Mid() == %f, want 6.8", rate.String(), rate.Mid())
+	}
+
+	//case 4:
+	for _, ask := range []float64{math.NaN(), math.Inf(1)} {
+		_, err = NewRate("USD", "CNY", 6.8, ask)
+		if err == nil {
+			t.Errorf("NewRate(\"USD\", \"CNY\", 6.8, %v) should be return an error, but no error return", ask)
+		}
+	}
+}
+
+func TestFxByRate(t *testing.T) {
+	rate, _ := NewRate("USD", "CNY", 6.7, 6.9)
+
+	//case 1: sell base currency at bid
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "100")
+	got, spread, _ := usdAmount.FxByRate(rate, 0)
+	want, _ := Factory.NewAmountInBasicUnit("CNY", "670")
+	wantSpread, _ := Factory.NewAmountInBasicUnit("CNY", "10")
+	if !want.IsEquals(got) || !wantSpread.IsEquals(spread) {
+		t.Errorf("%s FxByRate(%s, 0) == %s %s, want %s %s", usdAmount.String(), rate.String(), got.String(), spread.String(), want.String(), wantSpread.String())
+	}
+
+	//case 2: buy base currency at ask with markup
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "690")
+	got, spread, _ = cnyAmount.FxByRate(rate, 100)
+	want, _ = Factory.NewAmountInBasicUnit("USD", "99.01")
+	wantSpread, _ = Factory.NewAmountInBasicUnit("USD", "2.46")
+	if !want.IsEquals(got) || !wantSpread.IsEquals(spread) {
+		t.Errorf("%s FxByRate(%s, 100) == %s %s, want %s %s", cnyAmount.String(), rate.String(), got.String(), spread.String(), want.String(), wantSpread.String())
+	}
+
+	//case 3:
+	eurRate, _ := NewRate("EUR", "GBP", 0.85, 0.86)
+	_, _, err := usdAmount.FxByRate(eurRate, 0)
+	if err == nil {
+		t.Errorf("%s FxByRate(%s, 0) should be return an error, but no error return", usdAmount.String(), eurRate.String())
+	}
+
+	//case 4:
+	for _, markup := range []float64{-1, 10000, 20000, math.NaN()} {
+		_, _, err = usdAmount.FxByRate(rate, markup)
+		if err == nil {
+			t.Errorf("%s FxByRate(%s, %v) should be return an error, but no error return", usdAmount.String(), rate.String(), markup)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package currency
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+//LoadCsvToRateTable load CSV lines of from,to,rate (e.g.: USD,CNY,6.8) into table, lines starting with # are comments
+//return error with line number if a line is malformed or its rate is invalid
+func LoadCsvToRateTable(reader io.Reader, table *RateTable) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.Comment = '#'
+	csvReader.FieldsPerRecord = 3
+	csvReader.TrimLeadingSpace = true
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := csvReader.FieldPos(0)
+		rate, err := strconv.ParseFloat(record[2], 64)
+		if err != nil {
+			return fmt.Errorf("line %d: rate %q is not a numberic value", line, record[2])
+		}
+		if err := table.SetRate(record[0], record[1], rate); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+	}
+}
+
+//LoadRateFile load the rates file of name into a new rate table with pivot currency (empty means no pivot),
+//the file is ECB reference rates XML if name ends with .xml, otherwise CSV lines of from,to,rate
+//return error if the file can't be read, or is malformed
+func LoadRateFile(name string, pivot string) (*RateTable, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	table := NewRateTable(pivot)
+	if strings.HasSuffix(strings.ToLower(name), ".xml") {
+		_, err = LoadEcbXmlToRateTable(file, table)
+	} else {
+		err = LoadCsvToRateTable(file, table)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", name, err)
+	}
+	return table, nil
+}
@@ -0,0 +1,59 @@
+package currency
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+}
+
+func TestLoadCsvToRateTable(t *testing.T) {
+	//case 1:
+	table := NewRateTable("")
+	err := LoadCsvToRateTable(strings.NewReader("# from,to,rate\nUSD,CNY,6.8\n"), table)
+	rate, _ := table.Rate("CNY", "USD")
+	if err != nil || rate != 1/6.8 {
+		t.Errorf("LoadCsvToRateTable() == %v, rate CNY>USD %v, want nil %v", err, rate, 1/6.8)
+	}
+
+	//case 2:
+	err = LoadCsvToRateTable(strings.NewReader("USD,CNY,6.8\nUSD,JPY,x\n"), NewRateTable(""))
+	if err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Errorf("LoadCsvToRateTable(bad rate) == %v, want error of line 2", err)
+	}
+}
+
+func TestLoadRateFile(t *testing.T) {
+	//case 1:
+	name := filepath.Join(t.TempDir(), "rates.csv")
+	os.WriteFile(name, []byte("USD,CNY,6.8\n"), 0o644)
+	table, err := LoadRateFile(name, "")
+	if err != nil {
+		t.Fatalf("LoadRateFile(%q) == %v, want nil", name, err)
+	}
+	if rate, err := table.Rate("USD", "CNY"); err != nil || rate != 6.8 {
+		t.Errorf("LoadRateFile(%q) rate USD>CNY == %v %v, want 6.8", name, rate, err)
+	}
+
+	//case 2:
+	table, err = LoadRateFile("testdata/eurofxref-hist.xml", "EUR")
+	if err != nil || table == nil {
+		t.Errorf("LoadRateFile(\"testdata/eurofxref-hist.xml\") == %v, want nil", err)
+	}
+
+	//case 3: the error has the file name
+	os.WriteFile(name, []byte("USD,CNY,x\n"), 0o644)
+	_, err = LoadRateFile(name, "")
+	if err == nil || !strings.Contains(err.Error(), name) {
+		t.Errorf("LoadRateFile(%q) with bad rate == %v, want error of file name", name, err)
+	}
+	_, err = LoadRateFile(filepath.Join(t.TempDir(), "missing.csv"), "")
+	if err == nil {
+		t.Errorf("LoadRateFile(missing file) should be return an error, but no error return")
+	}
+}
@@ -0,0 +1,193 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+//LookupStrategy is the strategy of looking up a historical rate by time
+type LookupStrategy uint8
+
+const (
+	//LookupExact uses the rate effective exactly at the time
+	LookupExact LookupStrategy = iota
+	//LookupLastBefore uses the last known rate effective at or before the time
+	LookupLastBefore
+	//LookupNearest uses the rate whose effective time is nearest to the time, the earlier one wins a tie
+	LookupNearest
+)
+
+//historicalRate is a rate of currency pair effective from a time
+type historicalRate struct {
+	effective time.Time
+	rate      float64
+}
+
+//RateHistory is a time-series store of exchange rates keyed by currency pair and effective time
+type RateHistory struct {
+	series       map[string][]historicalRate //key is "FROM/TO", rates are sorted by effective time
+	strategy     LookupStrategy              //the default lookup strategy
+	maxStaleness time.Duration               //the max distance between lookup time and effective time, 0 means no limit
+	locker       *sync.RWMutex
+}
+
+//NewRateHistory create a new rate history with default lookup strategy and staleness limit
+//maxStaleness is the max distance between lookup time and effective time, 0 means no limit
+func NewRateHistory(strategy LookupStrategy, maxStaleness time.Duration) *RateHistory {
+	return &RateHistory{
+		series:       make(map[string][]historicalRate),
+		strategy:     strategy,
+		maxStaleness: maxStaleness,
+		locker:       new(sync.RWMutex),
+	}
+}
+
+//AddRate add the rate of currency pair effective from the time, 1 fromCurrencyCode = rate toCurrencyCode
+//the rate effective at the same time is replaced
+//return error if currency code is not a three-letter alphabetic code
+//return error if rate is not greater than 0
+func (history *RateHistory) AddRate(fromCurrencyCode string, toCurrencyCode string, effective time.Time, rate float64) error {
+	fromCurrencyCode, toCurrencyCode, err := normalizeCurrencyPair(fromCurrencyCode, toCurrencyCode)
+	if err != nil {
+		return err
+	}
+	if fromCurrencyCode == toCurrencyCode {
+		return errors.New("the currencies of rate are same")
+	}
+	if !(rate > 0) {
+		return errors.New("fx rate must be greater than 0")
+	}
+
+	history.locker.Lock()
+	defer history.locker.Unlock()
+	key := fromCurrencyCode + "/" + toCurrencyCode
+	rates := history.series[key]
+	i := sort.Search(len(rates), func(i int) bool { return !rates[i].effective.Before(effective) })
+	if i < len(rates) && rates[i].effective.Equal(effective) {
+		rates[i].rate = rate
+		return nil
+	}
+	rates = append(rates, historicalRate{})
+	copy(rates[i+1:], rates[i:])
+	rates[i] = historicalRate{effective, rate}
+	history.series[key] = rates
+	return nil
+}
+
+//Lookup returns the rate of currency pair at the time and its effective time, by using the default strategy
+//the opposite pair is used as LookupWithStrategy does
+//return error if no rate matches the strategy, or the matched rate exceeds the staleness limit
+func (history *RateHistory) Lookup(fromCurrencyCode string, toCurrencyCode string, asOf time.Time) (float64, time.Time, error) {
+	return history.LookupWithStrategy(fromCurrencyCode, toCurrencyCode, asOf, history.strategy)
+}
+
+//LookupWithStrategy returns the rate of currency pair at the time and its effective time, by using the strategy
+//the rates of the pair and the inverse of the opposite pair are both considered, the one nearer to the time wins,
+//the earlier one wins a tie, the pair wins if both are effective at the same time
+//return error if no rate matches the strategy, or the matched rate exceeds the staleness limit
+func (history *RateHistory) LookupWithStrategy(fromCurrencyCode string, toCurrencyCode string, asOf time.Time, strategy LookupStrategy) (float64, time.Time, error) {
+	fromCurrencyCode, toCurrencyCode, err := normalizeCurrencyPair(fromCurrencyCode, toCurrencyCode)
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	if fromCurrencyCode == toCurrencyCode {
+		return 1, asOf, nil
+	}
+
+	history.locker.RLock()
+	defer history.locker.RUnlock()
+	rates, exists := history.series[fromCurrencyCode+"/"+toCurrencyCode]
+	inverseRates, inverseExists := history.series[toCurrencyCode+"/"+fromCurrencyCode]
+	if !exists && !inverseExists {
+		return 0, time.Time{}, fmt.Errorf("fx rate of %s/%s is not found", fromCurrencyCode, toCurrencyCode)
+	}
+
+	matched, found := lookupHistoricalRate(rates, asOf, strategy)
+	if inverseMatched, inverseFound := lookupHistoricalRate(inverseRates, asOf, strategy); inverseFound {
+		inverseMatched.rate = 1 / inverseMatched.rate
+		if !found || nearerHistoricalRate(inverseMatched, matched, asOf) {
+			matched, found = inverseMatched, true
+		}
+	}
+	if !found {
+		return 0, time.Time{}, fmt.Errorf("fx rate of %s/%s at %s is not found", fromCurrencyCode, toCurrencyCode, asOf.Format(time.RFC3339))
+	}
+	if history.maxStaleness > 0 && absDuration(asOf.Sub(matched.effective)) > history.maxStaleness {
+		return 0, time.Time{}, fmt.Errorf("fx rate of %s/%s effective at %s is stale", fromCurrencyCode, toCurrencyCode, matched.effective.Format(time.RFC3339))
+	}
+	return matched.rate, matched.effective, nil
+}
+
+//nearerHistoricalRate returns true if rate is effective strictly nearer to asOf than other, the earlier one is nearer in a tie
+func nearerHistoricalRate(rate historicalRate, other historicalRate, asOf time.Time) bool {
+	distance, otherDistance := absDuration(asOf.Sub(rate.effective)), absDuration(asOf.Sub(other.effective))
+	if distance != otherDistance {
+		return distance < otherDistance
+	}
+	return rate.effective.Before(other.effective)
+}
+
+//AsOf returns a RateSource which looks up rates at the time by using the default strategy
+func (history *RateHistory) AsOf(asOf time.Time) RateSource {
+	return rateHistoryView{history, asOf}
+}
+
+//rateHistoryView is a view of rate history at a time
+type rateHistoryView struct {
+	history *RateHistory
+	asOf    time.Time
+}
+
+//Rate implements RateSource
+func (view rateHistoryView) Rate(fromCurrencyCode string, toCurrencyCode string) (float64, error) {
+	rate, _, err := view.history.Lookup(fromCurrencyCode, toCurrencyCode, view.asOf)
+	return rate, err
+}
+
+//FxAsOf foreign exchange by using the historical rate effective at the time
+//return error if history has no rate at the time
+//return error if targetCurrencyCode is not managed by factory
+func (amount Amount) FxAsOf(targetCurrencyCode string, history *RateHistory, asOf time.Time) (Amount, error) {
+	return amount.FxByRateSource(targetCurrencyCode, history.AsOf(asOf))
+}
+
+//lookupHistoricalRate find the rate matches the strategy in rates sorted by effective time
+func lookupHistoricalRate(rates []historicalRate, asOf time.Time, strategy LookupStrategy) (historicalRate, bool) {
+	//i is the index of first rate effective after asOf
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].effective.After(asOf) })
+	switch strategy {
+	case LookupExact:
+		if i > 0 && rates[i-1].effective.Equal(asOf) {
+			return rates[i-1], true
+		}
+	case LookupLastBefore:
+		if i > 0 {
+			return rates[i-1], true
+		}
+	case LookupNearest:
+		if i == 0 && len(rates) > 0 {
+			return rates[0], true
+		}
+		if i == len(rates) && len(rates) > 0 {
+			return rates[i-1], true
+		}
+		if i > 0 && i < len(rates) {
+			if rates[i].effective.Sub(asOf) < asOf.Sub(rates[i-1].effective) {
+				return rates[i], true
+			}
+			return rates[i-1], true
+		}
+	}
+	return historicalRate{}, false
+}
+
+//absDuration returns the absolute value of duration
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+	return duration
+}
@@ -0,0 +1,76 @@
+package currency
+
+import (
+	"testing"
+	"time"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+}
+
+func TestRateHistoryLookup(t *testing.T) {
+	day1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	day3 := day1.AddDate(0, 0, 2)
+	history := NewRateHistory(LookupLastBefore, 72*time.Hour)
+	history.AddRate("USD", "CNY", day3, 7)
+	history.AddRate("USD", "CNY", day1, 6)
+
+	//case 1: exact
+	_, _, err := history.LookupWithStrategy("USD", "CNY", day1.Add(time.Hour), LookupExact)
+	if err == nil {
+		t.Errorf("LookupWithStrategy(LookupExact) should be return an error, but no error return")
+	}
+
+	//case 2: last known before
+	rate, effective, _ := history.Lookup("USD", "CNY", day3.Add(-time.Hour))
+	if rate != 6 || !effective.Equal(day1) {
+		t.Errorf("Lookup(LookupLastBefore) == %f %s, want 6 %s", rate, effective, day1)
+	}
+
+	//case 3: nearest with inverse
+	rate, effective, _ = history.LookupWithStrategy("CNY", "USD", day3.Add(-time.Hour), LookupNearest)
+	if rate != float64(1)/7 || !effective.Equal(day3) {
+		t.Errorf("LookupWithStrategy(LookupNearest) == %f %s, want 1/7 %s", rate, effective, day3)
+	}
+
+	//case 4: no rate before
+	_, _, err = history.Lookup("USD", "CNY", day1.Add(-time.Hour))
+	if err == nil {
+		t.Errorf("Lookup() before first rate should be return an error, but no error return")
+	}
+
+	//case 5: stale
+	_, _, err = history.Lookup("USD", "CNY", day3.AddDate(0, 0, 4))
+	if err == nil {
+		t.Errorf("Lookup() of stale rate should be return an error, but no error return")
+	}
+
+	//case 6: the opposite pair has the only rate matching the strategy
+	history = NewRateHistory(LookupLastBefore, 0)
+	history.AddRate("USD", "CNY", day3, 7)
+	history.AddRate("CNY", "USD", day1, 0.125)
+	rate, effective, err = history.Lookup("USD", "CNY", day1.Add(time.Hour))
+	if err != nil || rate != 8 || !effective.Equal(day1) {
+		t.Errorf("Lookup(LookupLastBefore) with inverse earlier == %f %s %v, want 8 %s", rate, effective, err, day1)
+	}
+	rate, _, _ = history.Lookup("USD", "CNY", day3.Add(time.Hour))
+	if rate != 7 {
+		t.Errorf("Lookup(LookupLastBefore) with direct later == %f, want 7", rate)
+	}
+}
+
+func TestFxAsOf(t *testing.T) {
+	day1 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	history := NewRateHistory(LookupLastBefore, 0)
+	history.AddRate("USD", "CNY", day1, 6)
+	history.AddRate("USD", "CNY", day1.AddDate(0, 0, 1), 7)
+
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "2")
+	got, _ := usdAmount.FxAsOf("CNY", history, day1.Add(12*time.Hour))
+	want, _ := Factory.NewAmountInBasicUnit("CNY", "12")
+	if !want.IsEquals(got) {
+		t.Errorf("%s FxAsOf(\"CNY\") == %s, want %s", usdAmount.String(), got.String(), want.String())
+	}
+}
@@ -0,0 +1,168 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//RateSource provides exchange rates between currencies
+type RateSource interface {
+	//Rate returns how many units of target currency one unit of source currency buys
+	Rate(fromCurrencyCode string, toCurrencyCode string) (float64, error)
+}
+
+//RateTable maintains exchange rates of currency pairs,
+//inverse rates are derived automatically, and cross rates are computed through
+//the pivot currency or the shortest path of available pairs
+type RateTable struct {
+	rates  map[string]map[string]float64 //rates[from][to], only the pairs set by SetRate
+	pivot  string                        //the preferred intermediate currency of cross rates
+	locker *sync.RWMutex
+}
+
+//NewRateTable create a new rate table
+//pivotCurrencyCode is the preferred intermediate currency of cross rates (e.g: USD), empty string means no pivot
+func NewRateTable(pivotCurrencyCode string) *RateTable {
+	return &RateTable{
+		rates:  make(map[string]map[string]float64),
+		pivot:  strings.ToUpper(strings.TrimSpace(pivotCurrencyCode)),
+		locker: new(sync.RWMutex),
+	}
+}
+
+//Pivot returns the pivot currency code of rate table
+func (table *RateTable) Pivot() string {
+	return table.pivot
+}
+
+//SetRate set the rate of currency pair, 1 fromCurrencyCode = rate toCurrencyCode
+//return error if currency code is not a three-letter alphabetic code
+//return error if rate is not greater than 0
+func (table *RateTable) SetRate(fromCurrencyCode string, toCurrencyCode string, rate float64) error {
+	fromCurrencyCode, toCurrencyCode, err := normalizeCurrencyPair(fromCurrencyCode, toCurrencyCode)
+	if err != nil {
+		return err
+	}
+	if fromCurrencyCode == toCurrencyCode {
+		return errors.New("the currencies of rate are same")
+	}
+	if !(rate > 0) {
+		return errors.New("fx rate must be greater than 0")
+	}
+
+	table.locker.Lock()
+	defer table.locker.Unlock()
+	toRates, exists := table.rates[fromCurrencyCode]
+	if !exists {
+		toRates = make(map[string]float64)
+		table.rates[fromCurrencyCode] = toRates
+	}
+	toRates[toCurrencyCode] = rate
+	return nil
+}
+
+//Rate returns the rate of currency pair, implements RateSource
+func (table *RateTable) Rate(fromCurrencyCode string, toCurrencyCode string) (float64, error) {
+	rate, _, err := table.CrossRate(fromCurrencyCode, toCurrencyCode)
+	return rate, err
+}
+
+//CrossRate returns the rate of currency pair and the path of currency codes used to compute it (e.g: [CNY USD JPY])
+//the rate is looked up in order: direct or inverse rate, cross rate through pivot, shortest path of available pairs
+//return error if there is no path between two currencies
+func (table *RateTable) CrossRate(fromCurrencyCode string, toCurrencyCode string) (float64, []string, error) {
+	fromCurrencyCode, toCurrencyCode, err := normalizeCurrencyPair(fromCurrencyCode, toCurrencyCode)
+	if err != nil {
+		return 0, nil, err
+	}
+	if fromCurrencyCode == toCurrencyCode {
+		return 1, []string{fromCurrencyCode}, nil
+	}
+
+	table.locker.RLock()
+	defer table.locker.RUnlock()
+
+	if rate, exists := table.pairRate(fromCurrencyCode, toCurrencyCode); exists {
+		return rate, []string{fromCurrencyCode, toCurrencyCode}, nil
+	}
+
+	if table.pivot != "" && table.pivot != fromCurrencyCode && table.pivot != toCurrencyCode {
+		fromRate, fromExists := table.pairRate(fromCurrencyCode, table.pivot)
+		toRate, toExists := table.pairRate(table.pivot, toCurrencyCode)
+		if fromExists && toExists {
+			return fromRate * toRate, []string{fromCurrencyCode, table.pivot, toCurrencyCode}, nil
+		}
+	}
+
+	path := table.shortestPath(fromCurrencyCode, toCurrencyCode)
+	if path == nil {
+		return 0, nil, fmt.Errorf("fx rate of %s/%s is not found", fromCurrencyCode, toCurrencyCode)
+	}
+	rate := float64(1)
+	for i := 1; i < len(path); i++ {
+		pairRate, _ := table.pairRate(path[i-1], path[i])
+		rate = rate * pairRate
+	}
+	return rate, path, nil
+}
+
+//pairRate returns the direct rate of currency pair, or the inverse of the opposite pair
+func (table *RateTable) pairRate(fromCurrencyCode string, toCurrencyCode string) (float64, bool) {
+	if rate, exists := table.rates[fromCurrencyCode][toCurrencyCode]; exists {
+		return rate, true
+	}
+	if rate, exists := table.rates[toCurrencyCode][fromCurrencyCode]; exists {
+		return 1 / rate, true
+	}
+	return 0, false
+}
+
+//shortestPath returns the shortest path of currency codes between two currencies by breadth-first search,
+//returns nil if there is no path
+func (table *RateTable) shortestPath(fromCurrencyCode string, toCurrencyCode string) []string {
+	neighbors := make(map[string][]string)
+	for from, toRates := range table.rates {
+		for to := range toRates {
+			neighbors[from] = append(neighbors[from], to)
+			neighbors[to] = append(neighbors[to], from)
+		}
+	}
+	for _, codes := range neighbors {
+		sort.Strings(codes) //keep the search result stable
+	}
+
+	previous := map[string]string{fromCurrencyCode: ""}
+	queue := []string{fromCurrencyCode}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == toCurrencyCode {
+			var path []string
+			for code := current; code != ""; code = previous[code] {
+				path = append([]string{code}, path...)
+			}
+			return path
+		}
+		for _, next := range neighbors[current] {
+			if _, visited := previous[next]; !visited {
+				previous[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+//normalizeCurrencyPair upper and trim two currency codes
+//return error if any of them is not a three-letter alphabetic code
+func normalizeCurrencyPair(fromCurrencyCode string, toCurrencyCode string) (string, string, error) {
+	fromCurrencyCode = strings.ToUpper(strings.TrimSpace(fromCurrencyCode))
+	toCurrencyCode = strings.ToUpper(strings.TrimSpace(toCurrencyCode))
+	if !currencyCodeReg.MatchString(fromCurrencyCode) || !currencyCodeReg.MatchString(toCurrencyCode) {
+		return "", "", errors.New("the currency code is not three-letter alphabetic code")
+	}
+	return fromCurrencyCode, toCurrencyCode, nil
+}
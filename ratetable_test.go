@@ -0,0 +1,93 @@
+package currency
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+	Factory.NewCurrency("EUR", 2)
+	Factory.NewCurrency("JPY", 0)
+}
+
+func TestRateTableSetRate(t *testing.T) {
+	table := NewRateTable("USD")
+
+	//case 1:
+	err := table.SetRate("US", "CNY", 6.8)
+	if err == nil {
+		t.Errorf("SetRate(\"US\", \"CNY\", 6.8) should be return an error, but no error return")
+	}
+
+	//case 2:
+	err = table.SetRate("USD", "CNY", 0)
+	if err == nil {
+		t.Errorf("SetRate(\"USD\", \"CNY\", 0) should be return an error, but no error return")
+	}
+
+	//case 3:
+	err = table.SetRate("usd", "usd", 1)
+	if err == nil {
+		t.Errorf("SetRate(\"usd\", \"usd\", 1) should be return an error, but no error return")
+	}
+}
+
+func TestRateTableCrossRate(t *testing.T) {
+	table := NewRateTable("USD")
+	table.SetRate("USD", "CNY", 8)
+	table.SetRate("usd", "jpy", 160)
+	table.SetRate("EUR", "GBP", 0.8)
+	table.SetRate("GBP", "CHF", 1.25)
+
+	//case 1: direct rate
+	rate, path, _ := table.CrossRate("USD", "CNY")
+	if rate != 8 || !reflect.DeepEqual(path, []string{"USD", "CNY"}) {
+		t.Errorf("CrossRate(\"USD\", \"CNY\") == %f %v, want 8 [USD CNY]", rate, path)
+	}
+
+	//case 2: inverse rate
+	rate, path, _ = table.CrossRate("CNY", "USD")
+	if rate != 0.125 || !reflect.DeepEqual(path, []string{"CNY", "USD"}) {
+		t.Errorf("CrossRate(\"CNY\", \"USD\") == %f %v, want 0.125 [CNY USD]", rate, path)
+	}
+
+	//case 3: cross rate through pivot
+	rate, path, _ = table.CrossRate("cny", "jpy")
+	if rate != 20 || !reflect.DeepEqual(path, []string{"CNY", "USD", "JPY"}) {
+		t.Errorf("CrossRate(\"cny\", \"jpy\") == %f %v, want 20 [CNY USD JPY]", rate, path)
+	}
+
+	//case 4: shortest path without pivot
+	rate, path, _ = table.CrossRate("CHF", "EUR")
+	if math.Abs(rate-1) > 1e-9 || !reflect.DeepEqual(path, []string{"CHF", "GBP", "EUR"}) {
+		t.Errorf("CrossRate(\"CHF\", \"EUR\") == %f %v, want 1 [CHF GBP EUR]", rate, path)
+	}
+
+	//case 5: no path
+	_, _, err := table.CrossRate("USD", "EUR")
+	if err == nil {
+		t.Errorf("CrossRate(\"USD\", \"EUR\") should be return an error, but no error return")
+	}
+}
+
+func TestFxByRateTable(t *testing.T) {
+	table := NewRateTable("USD")
+	table.SetRate("USD", "CNY", 8)
+	table.SetRate("USD", "JPY", 160)
+
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "10")
+	got, path, _ := cnyAmount.FxByRateTable("JPY", table)
+	want, _ := Factory.NewAmountInBasicUnit("JPY", "200")
+	if !want.IsEquals(got) || !reflect.DeepEqual(path, []string{"CNY", "USD", "JPY"}) {
+		t.Errorf("%s FxByRateTable(\"JPY\") == %s %v, want %s [CNY USD JPY]", cnyAmount.String(), got.String(), path, want.String())
+	}
+
+	got, _ = cnyAmount.FxByRateSource("USD", table)
+	want, _ = Factory.NewAmountInBasicUnit("USD", "1.25")
+	if !want.IsEquals(got) {
+		t.Errorf("%s FxByRateSource(\"USD\") == %s, want %s", cnyAmount.String(), got.String(), want.String())
+	}
+}
@@ -0,0 +1,384 @@
+//Package reconcile matches two lists of amount transactions, e.g.: bank statement lines and internal records
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+//Transaction is a transaction to reconcile
+type Transaction struct {
+	ID        string
+	Amount    currency.Amount
+	Date      time.Time
+	Reference string //optional, transactions with different non-empty references never match
+}
+
+//Options is the options of reconciliation
+type Options struct {
+	DateWindow   time.Duration //the max distance between dates of matched transactions, 0 means same instant
+	Tolerance    int64         //the max difference of matched amounts in minor units
+	MaxGroupSize int           //the max number of transactions on the many side of one-to-many and many-to-one matches, less than 2 disables them
+	SearchLimit  int           //the max number of groups tried for a transaction in one-to-many and many-to-one matches, 0 means DefaultSearchLimit
+}
+
+//DefaultSearchLimit is the default max number of groups tried for a transaction
+const DefaultSearchLimit = 100000
+
+//ErrSearchLimit means the group search of a transaction tried more groups than Options.SearchLimit,
+//narrow the candidates by DateWindow or lower MaxGroupSize
+var ErrSearchLimit = errors.New("group search exceeds the limit")
+
+//MatchKind is the kind of match
+type MatchKind uint8
+
+const (
+	//OneToOne matches a left transaction to a right transaction
+	OneToOne MatchKind = iota
+	//OneToMany matches a left transaction to several right transactions
+	OneToMany
+	//ManyToOne matches several left transactions to a right transaction
+	ManyToOne
+)
+
+//String returns the name of match kind
+func (kind MatchKind) String() string {
+	switch kind {
+	case OneToMany:
+		return "ONE_TO_MANY"
+	case ManyToOne:
+		return "MANY_TO_ONE"
+	default:
+		return "ONE_TO_ONE"
+	}
+}
+
+//Match is a group of matched left and right transactions
+type Match struct {
+	Kind       MatchKind
+	Left       []Transaction
+	Right      []Transaction
+	Difference currency.Amount //sum of left minus sum of right, within tolerance for matched pairs
+	Reason     string          //why the pair is suspicious, empty for matched pairs
+}
+
+//Result is the result of reconciliation
+type Result struct {
+	Matched        []Match
+	Suspicious     []Match //one-to-one pairs look related but failed to match, e.g.: same reference with different amount
+	UnmatchedLeft  []Transaction
+	UnmatchedRight []Transaction
+}
+
+//Reconcile match left transactions against right transactions.
+//One-to-one matches are tried first, exact amounts with same reference win, then nearest date;
+//then one-to-many and many-to-one matches; the remaining related pairs are reported as suspicious
+//return error if the sum of a match overflows
+//return ErrSearchLimit error if the group search of a transaction exceeds options.SearchLimit
+func Reconcile(left []Transaction, right []Transaction, options Options) (Result, error) {
+	state := reconciliation{
+		left:      left,
+		right:     right,
+		options:   options,
+		leftUsed:  make([]bool, len(left)),
+		rightUsed: make([]bool, len(right)),
+	}
+	var result Result
+
+	//one-to-one passes, from the strictest to the loosest
+	passes := []struct {
+		exactAmount   bool
+		sameReference bool
+	}{{true, true}, {true, false}, {false, true}, {false, false}}
+	for _, pass := range passes {
+		for i := range left {
+			if state.leftUsed[i] {
+				continue
+			}
+			if j := state.bestOneToOne(i, pass.exactAmount, pass.sameReference); j >= 0 {
+				match, err := newMatch(OneToOne, []Transaction{left[i]}, []Transaction{right[j]}, "")
+				if err != nil {
+					return Result{}, err
+				}
+				state.leftUsed[i], state.rightUsed[j] = true, true
+				result.Matched = append(result.Matched, match)
+			}
+		}
+	}
+
+	if options.MaxGroupSize >= 2 {
+		for i := range left {
+			if state.leftUsed[i] {
+				continue
+			}
+			group, err := findGroup(left[i], right, state.rightUsed, options)
+			if err != nil {
+				return Result{}, err
+			}
+			if group != nil {
+				var members []Transaction
+				for _, j := range group {
+					members = append(members, right[j])
+				}
+				match, err := newMatch(OneToMany, []Transaction{left[i]}, members, "")
+				if err != nil {
+					return Result{}, err
+				}
+				state.leftUsed[i] = true
+				for _, j := range group {
+					state.rightUsed[j] = true
+				}
+				result.Matched = append(result.Matched, match)
+			}
+		}
+		for j := range right {
+			if state.rightUsed[j] {
+				continue
+			}
+			group, err := findGroup(right[j], left, state.leftUsed, options)
+			if err != nil {
+				return Result{}, err
+			}
+			if group != nil {
+				var members []Transaction
+				for _, i := range group {
+					members = append(members, left[i])
+				}
+				match, err := newMatch(ManyToOne, members, []Transaction{right[j]}, "")
+				if err != nil {
+					return Result{}, err
+				}
+				state.rightUsed[j] = true
+				for _, i := range group {
+					state.leftUsed[i] = true
+				}
+				result.Matched = append(result.Matched, match)
+			}
+		}
+	}
+
+	for i := range left {
+		if state.leftUsed[i] {
+			continue
+		}
+		for j := range right {
+			if state.rightUsed[j] {
+				continue
+			}
+			if reason := suspiciousReason(left[i], right[j], options); reason != "" {
+				match, err := newMatch(OneToOne, []Transaction{left[i]}, []Transaction{right[j]}, reason)
+				if err != nil {
+					return Result{}, err
+				}
+				state.leftUsed[i], state.rightUsed[j] = true, true
+				result.Suspicious = append(result.Suspicious, match)
+				break
+			}
+		}
+	}
+
+	for i, used := range state.leftUsed {
+		if !used {
+			result.UnmatchedLeft = append(result.UnmatchedLeft, left[i])
+		}
+	}
+	for j, used := range state.rightUsed {
+		if !used {
+			result.UnmatchedRight = append(result.UnmatchedRight, right[j])
+		}
+	}
+	return result, nil
+}
+
+//reconciliation is the state of a reconciliation
+type reconciliation struct {
+	left      []Transaction
+	right     []Transaction
+	options   Options
+	leftUsed  []bool
+	rightUsed []bool
+}
+
+//bestOneToOne returns the index of unused right transaction matching left[i] with the nearest date, -1 if not found
+func (state *reconciliation) bestOneToOne(i int, exactAmount bool, sameReference bool) int {
+	best, bestDistance := -1, time.Duration(0)
+	for j := range state.right {
+		if state.rightUsed[j] {
+			continue
+		}
+		l, r := state.left[i], state.right[j]
+		if !compatible(l, r, state.options) {
+			continue
+		}
+		if exactAmount && !l.Amount.IsEquals(r.Amount) || !exactAmount && !withinTolerance(l.Amount.MinorUnitValue(), r.Amount.MinorUnitValue(), state.options.Tolerance) {
+			continue
+		}
+		if sameReference && (l.Reference == "" || !sameReferences(l.Reference, r.Reference)) {
+			continue
+		}
+		distance := absDuration(l.Date.Sub(r.Date))
+		if best < 0 || distance < bestDistance {
+			best, bestDistance = j, distance
+		}
+	}
+	return best
+}
+
+//findGroup returns the indexes of unused candidates whose sum matches target within tolerance,
+//at least 2 and at most MaxGroupSize candidates, nil if not found.
+//The candidates are searched in ascending order of amount, and a branch is pruned
+//if its sum can't reach the range of target by the remaining candidates
+//return ErrSearchLimit error if more than SearchLimit groups are tried
+func findGroup(target Transaction, candidates []Transaction, used []bool, options Options) ([]int, error) {
+	var pool []int
+	for i, candidate := range candidates {
+		if !used[i] && compatible(target, candidate, options) {
+			pool = append(pool, i)
+		}
+	}
+	sort.SliceStable(pool, func(a, b int) bool {
+		return candidates[pool[a]].Amount.MinorUnitValue() < candidates[pool[b]].Amount.MinorUnitValue()
+	})
+	values := make([]int64, len(pool))
+	for k, index := range pool {
+		values[k] = candidates[index].Amount.MinorUnitValue()
+	}
+	low := saturatedAdd(target.Amount.MinorUnitValue(), -options.Tolerance)
+	high := saturatedAdd(target.Amount.MinorUnitValue(), options.Tolerance)
+	limit := options.SearchLimit
+	if limit <= 0 {
+		limit = DefaultSearchLimit
+	}
+
+	var group []int
+	tried := 0
+	var search func(start int, sum int64) (bool, error)
+	search = func(start int, sum int64) (bool, error) {
+		if len(group) >= 2 && sum >= low && sum <= high {
+			return true, nil
+		}
+		slots := options.MaxGroupSize - len(group)
+		if slots == 0 || start == len(pool) {
+			return false, nil
+		}
+		//the smallest values are the next slots ones, the largest values are the last slots ones
+		lowest, highest := sum, sum
+		for k := start; k < min(start+slots, len(pool)) && values[k] < 0; k++ {
+			lowest = saturatedAdd(lowest, values[k])
+		}
+		for k := len(pool) - 1; k >= max(len(pool)-slots, start) && values[k] > 0; k-- {
+			highest = saturatedAdd(highest, values[k])
+		}
+		if highest < low || lowest > high {
+			return false, nil
+		}
+		for k := start; k < len(pool); k++ {
+			next := saturatedAdd(sum, values[k])
+			if values[k] >= 0 && next > high {
+				break //the values after k are not less than values[k]
+			}
+			if tried++; tried > limit {
+				return false, fmt.Errorf("%w: transaction %s tried %d groups", ErrSearchLimit, target.ID, limit)
+			}
+			group = append(group, pool[k])
+			found, err := search(k+1, next)
+			if found || err != nil {
+				return found, err
+			}
+			group = group[:len(group)-1]
+		}
+		return false, nil
+	}
+	found, err := search(0, 0)
+	if err != nil || !found {
+		return nil, err
+	}
+	return group, nil
+}
+
+//suspiciousReason returns why two unmatched transactions look related, empty if they are not related
+func suspiciousReason(left Transaction, right Transaction, options Options) string {
+	if left.Amount.CurrencyCode() != right.Amount.CurrencyCode() {
+		return ""
+	}
+	amountMatches := withinTolerance(left.Amount.MinorUnitValue(), right.Amount.MinorUnitValue(), options.Tolerance)
+	dateMatches := absDuration(left.Date.Sub(right.Date)) <= options.DateWindow
+	referenceMatches := left.Reference != "" && sameReferences(left.Reference, right.Reference)
+	switch {
+	case referenceMatches && !amountMatches:
+		return "same reference with different amount"
+	case referenceMatches && !dateMatches:
+		return "same reference out of date window"
+	case amountMatches && dateMatches:
+		return "same amount with different reference"
+	}
+	return ""
+}
+
+//compatible return true if two transactions have same currency, dates within window and compatible references
+func compatible(left Transaction, right Transaction, options Options) bool {
+	if left.Amount.CurrencyCode() != right.Amount.CurrencyCode() {
+		return false
+	}
+	if absDuration(left.Date.Sub(right.Date)) > options.DateWindow {
+		return false
+	}
+	return left.Reference == "" || right.Reference == "" || sameReferences(left.Reference, right.Reference)
+}
+
+//newMatch create a match with the difference of sums
+//return error if the difference overflows
+func newMatch(kind MatchKind, left []Transaction, right []Transaction, reason string) (Match, error) {
+	var err error
+	difference := left[0].Amount
+	for _, transaction := range left[1:] {
+		if difference, err = difference.Add(transaction.Amount); err != nil {
+			return Match{}, err
+		}
+	}
+	for _, transaction := range right {
+		if difference, err = difference.Minus(transaction.Amount); err != nil {
+			return Match{}, err
+		}
+	}
+	return Match{Kind: kind, Left: left, Right: right, Difference: difference, Reason: reason}, nil
+}
+
+//sameReferences return true if two references are same, ignoring case and spaces around
+func sameReferences(a string, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+//withinTolerance return true if the distance between a and b is not greater than tolerance, without overflow
+func withinTolerance(a int64, b int64, tolerance int64) bool {
+	if a < b {
+		a, b = b, a
+	}
+	return uint64(a)-uint64(b) <= uint64(max(tolerance, 0))
+}
+
+//saturatedAdd returns a + b, clamped to the range of int64 instead of overflowing
+func saturatedAdd(a int64, b int64) int64 {
+	sum := a + b
+	switch {
+	case a > 0 && b > 0 && sum < 0:
+		return math.MaxInt64
+	case a < 0 && b < 0 && sum >= 0:
+		return math.MinInt64
+	}
+	return sum
+}
+
+//absDuration returns the absolute value of duration
+func absDuration(duration time.Duration) time.Duration {
+	if duration < 0 {
+		return -duration
+	}
+	return duration
+}
@@ -0,0 +1,130 @@
+package reconcile
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+
+	currency "github.com/ciferliu/gocurrency"
+)
+
+func init() {
+	currency.Factory.NewCurrency("USD", 2)
+	currency.Factory.NewCurrency("CNY", 2)
+}
+
+var day = time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC)
+
+func transaction(id string, currencyCode string, value string, days int, reference string) Transaction {
+	amount, _ := currency.Factory.NewAmountInBasicUnit(currencyCode, value)
+	return Transaction{ID: id, Amount: amount, Date: day.AddDate(0, 0, days), Reference: reference}
+}
+
+func TestReconcileOneToOne(t *testing.T) {
+	bank := []Transaction{
+		transaction("b1", "USD", "10", 0, ""),
+		transaction("b2", "USD", "10", 1, "INV-2"),
+		transaction("b3", "USD", "5.01", 0, ""),
+		transaction("b4", "CNY", "8", 0, ""),
+	}
+	books := []Transaction{
+		transaction("r1", "USD", "10", 0, "inv-1"),
+		transaction("r2", "USD", "10", 0, "INV-2"),
+		transaction("r3", "USD", "5", 0, ""),
+		transaction("r4", "USD", "8", 0, ""),
+	}
+	result, err := Reconcile(bank, books, Options{DateWindow: 48 * time.Hour, Tolerance: 1})
+	if err != nil {
+		t.Fatalf("Reconcile() == %v, want nil", err)
+	}
+
+	//case 1: same reference wins over nearer date, and tolerance
+	if len(result.Matched) != 3 {
+		t.Fatalf("Reconcile() matched %d, want 3", len(result.Matched))
+	}
+	if result.Matched[0].Left[0].ID != "b2" || result.Matched[0].Right[0].ID != "r2" {
+		t.Errorf("Reconcile() first match == %s-%s, want b2-r2", result.Matched[0].Left[0].ID, result.Matched[0].Right[0].ID)
+	}
+	if result.Matched[1].Left[0].ID != "b1" || result.Matched[1].Right[0].ID != "r1" {
+		t.Errorf("Reconcile() second match == %s-%s, want b1-r1", result.Matched[1].Left[0].ID, result.Matched[1].Right[0].ID)
+	}
+	if difference := result.Matched[2].Difference.MinorUnitValue(); difference != 1 {
+		t.Errorf("Reconcile() third match difference == %d, want 1", difference)
+	}
+
+	//case 2: different currency is unmatched
+	if len(result.UnmatchedLeft) != 1 || result.UnmatchedLeft[0].ID != "b4" || len(result.UnmatchedRight) != 1 || result.UnmatchedRight[0].ID != "r4" {
+		t.Errorf("Reconcile() unmatched == %v %v, want [b4] [r4]", result.UnmatchedLeft, result.UnmatchedRight)
+	}
+}
+
+func TestReconcileGroups(t *testing.T) {
+	bank := []Transaction{
+		transaction("b1", "USD", "30", 0, ""),
+		transaction("b2", "USD", "4", 0, ""),
+		transaction("b3", "USD", "6", 0, ""),
+	}
+	books := []Transaction{
+		transaction("r1", "USD", "10", 0, ""),
+		transaction("r2", "USD", "20", 1, ""),
+		transaction("r3", "USD", "10", 0, ""),
+	}
+	result, err := Reconcile(bank, books, Options{DateWindow: 24 * time.Hour, MaxGroupSize: 3})
+	if err != nil {
+		t.Fatalf("Reconcile() == %v, want nil", err)
+	}
+
+	//case 1:
+	if len(result.Matched) != 2 || result.Matched[0].Kind != OneToMany || len(result.Matched[0].Right) != 2 || result.Matched[1].Kind != ManyToOne {
+		t.Errorf("Reconcile() == %v, want a one-to-many match and a many-to-one match", result.Matched)
+	}
+}
+
+func TestReconcileSuspicious(t *testing.T) {
+	bank := []Transaction{transaction("b1", "USD", "10", 0, "INV-1")}
+	books := []Transaction{transaction("r1", "USD", "100", 0, "INV-1")}
+	result, err := Reconcile(bank, books, Options{})
+	if err != nil {
+		t.Fatalf("Reconcile() == %v, want nil", err)
+	}
+
+	//case 1:
+	if len(result.Suspicious) != 1 || result.Suspicious[0].Reason != "same reference with different amount" {
+		t.Errorf("Reconcile() suspicious == %v, want same reference with different amount", result.Suspicious)
+	}
+	if len(result.UnmatchedLeft) != 0 || len(result.UnmatchedRight) != 0 {
+		t.Errorf("Reconcile() unmatched == %v %v, want none", result.UnmatchedLeft, result.UnmatchedRight)
+	}
+}
+
+func TestReconcileLargeGroups(t *testing.T) {
+	//case 1: pruned, no group of at most 5 can reach the target
+	bank := []Transaction{transaction("b1", "USD", "1000", 0, "")}
+	var books []Transaction
+	for i := 0; i < 400; i++ {
+		books = append(books, transaction("r"+strconv.Itoa(i), "USD", "1", 0, ""))
+	}
+	result, err := Reconcile(bank, books, Options{MaxGroupSize: 5})
+	if err != nil || len(result.Matched) != 0 || len(result.UnmatchedRight) != 400 {
+		t.Errorf("Reconcile() of 400 candidates == %d matched %v, want none matched", len(result.Matched), err)
+	}
+
+	//case 2: found among 400 candidates
+	books = append(books, transaction("r400", "USD", "997", 0, ""))
+	result, err = Reconcile(bank, books, Options{MaxGroupSize: 5})
+	if err != nil || len(result.Matched) != 1 || len(result.Matched[0].Right) != 4 {
+		t.Errorf("Reconcile() of 401 candidates == %v %v, want a one-to-many match of 4", result.Matched, err)
+	}
+
+	//case 3: the search is bounded when pruning can't help, all candidates are even and the target is odd
+	bank = []Transaction{transaction("b1", "USD", "1000.01", 0, "")}
+	books = nil
+	for i := 0; i < 300; i++ {
+		books = append(books, transaction("r"+strconv.Itoa(i), "USD", strconv.Itoa(100+2*i), 0, ""))
+	}
+	_, err = Reconcile(bank, books, Options{MaxGroupSize: 5})
+	if !errors.Is(err, ErrSearchLimit) {
+		t.Errorf("Reconcile() of 300 candidates without match == %v, want %v", err, ErrSearchLimit)
+	}
+}
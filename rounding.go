@@ -0,0 +1,261 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+//RoundingMode is the algorithm of rounding a value to the minor unit of currency
+type RoundingMode uint8
+
+const (
+	//RoundHalfEven rounds to nearest, ties to even (banker's rounding), it's the default rounding mode
+	RoundHalfEven RoundingMode = iota
+	//RoundHalfUp rounds to nearest, ties away from zero
+	RoundHalfUp
+	//RoundHalfDown rounds to nearest, ties toward zero
+	RoundHalfDown
+	//RoundUp rounds away from zero
+	RoundUp
+	//RoundDown rounds toward zero (truncation)
+	RoundDown
+	//RoundCeiling rounds toward positive infinity
+	RoundCeiling
+	//RoundFloor rounds toward negative infinity
+	RoundFloor
+)
+
+var roundingModeNames = []string{"HALF_EVEN", "HALF_UP", "HALF_DOWN", "UP", "DOWN", "CEILING", "FLOOR"}
+
+//String returns the name of rounding mode(e.g.: HALF_EVEN)
+func (mode RoundingMode) String() string {
+	if int(mode) < len(roundingModeNames) {
+		return roundingModeNames[mode]
+	}
+	return "RoundingMode(" + strconv.Itoa(int(mode)) + ")"
+}
+
+//MarshalText implements encoding.TextMarshaler
+func (mode RoundingMode) MarshalText() ([]byte, error) {
+	if err := mode.validate(); err != nil {
+		return nil, err
+	}
+	return []byte(mode.String()), nil
+}
+
+//UnmarshalText implements encoding.TextUnmarshaler
+func (mode *RoundingMode) UnmarshalText(text []byte) error {
+	for i, name := range roundingModeNames {
+		if name == string(text) {
+			*mode = RoundingMode(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("rounding mode %q is invalid", text)
+}
+
+//validate returns error if mode is not one of the rounding modes (e.g.: RoundingMode(9))
+func (mode RoundingMode) validate() error {
+	if int(mode) >= len(roundingModeNames) {
+		return fmt.Errorf("rounding mode %d is invalid", mode)
+	}
+	return nil
+}
+
+//roundRat rounds value to an integer by using the rounding mode, the mode must be validated by caller
+func roundRat(value *big.Rat, mode RoundingMode) *big.Int {
+	quo, rem := new(big.Int).QuoRem(value.Num(), value.Denom(), new(big.Int)) //quo is truncated toward zero
+	if rem.Sign() == 0 {
+		return quo
+	}
+
+	twiceRem := new(big.Int).Abs(rem)
+	twiceRem.Lsh(twiceRem, 1)
+	halfCmp := twiceRem.Cmp(value.Denom()) //compare the discarded fraction with 0.5
+	var awayFromZero bool
+	switch mode {
+	case RoundHalfEven:
+		awayFromZero = halfCmp > 0 || (halfCmp == 0 && quo.Bit(0) == 1)
+	case RoundHalfUp:
+		awayFromZero = halfCmp >= 0
+	case RoundHalfDown:
+		awayFromZero = halfCmp > 0
+	case RoundUp:
+		awayFromZero = true
+	case RoundDown:
+		awayFromZero = false
+	case RoundCeiling:
+		awayFromZero = value.Sign() > 0
+	case RoundFloor:
+		awayFromZero = value.Sign() < 0
+	}
+	if awayFromZero {
+		quo.Add(quo, big.NewInt(int64(value.Sign())))
+	}
+	return quo
+}
+
+//roundRatToMinorUnit rounds value in basic unit to an integer in minor unit by using the rounding mode
+//return error if mode is invalid, or the result overflows int64
+func roundRatToMinorUnit(value *big.Rat, minorUnitDigits uint8, mode RoundingMode) (int64, error) {
+	if err := mode.validate(); err != nil {
+		return 0, err
+	}
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(pow10Int(minorUnitDigits)))
+	rounded := roundRat(scaled, mode)
+	if !rounded.IsInt64() {
+		return 0, errors.New("amount value is out of range")
+	}
+	return rounded.Int64(), nil
+}
+
+//parseBasicUnitValue parses the decimal text of basic unit value (e.g.: -1.5, 1234.5678, 1.5e3) into
+//minor unit value rounded by mode, without float conversion, so that every digit of text is taken into account,
+//also returns the number of significant fraction digits beyond minorUnitDigits (e.g.: USD 1.2345 => 2, USD 1.2300 => 0)
+//return error if mode is invalid
+//return error if text is not a decimal value
+//return error if the value is out of range
+func parseBasicUnitValue(text string, minorUnitDigits uint8, mode RoundingMode) (int64, int, error) {
+	if err := mode.validate(); err != nil {
+		return 0, 0, err
+	}
+	negative := false
+	if len(text) > 0 && (text[0] == '+' || text[0] == '-') {
+		negative = text[0] == '-'
+		text = text[1:]
+	}
+	mantissa, exponent := text, 0
+	if i := strings.IndexAny(text, "eE"); i >= 0 {
+		exp, err := strconv.Atoi(text[i+1:])
+		if err != nil || exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return 0, 0, errors.New("basicUnitValue is not a numberic value")
+		}
+		mantissa, exponent = text[:i], exp
+	}
+	integer, fraction := mantissa, ""
+	if i := strings.IndexByte(mantissa, '.'); i >= 0 {
+		integer, fraction = mantissa[:i], mantissa[i+1:]
+	}
+	if (integer == "" && fraction == "") || !isDigits(integer) || !isDigits(fraction) {
+		return 0, 0, errors.New("basicUnitValue is not a numberic value")
+	}
+
+	//the digits of integer and fraction are indexed from 0, digits out of them are 0
+	digitAt := func(i int) uint64 {
+		switch {
+		case i < 0 || i >= len(integer)+len(fraction):
+			return 0
+		case i < len(integer):
+			return uint64(integer[i] - '0')
+		default:
+			return uint64(fraction[i-len(integer)] - '0')
+		}
+	}
+	limit := uint64(math.MaxInt64)
+	if negative {
+		limit++ //math.MinInt64
+	}
+	keep := len(integer) + exponent + int(minorUnitDigits) //the digits before keep are the minor unit value
+	var magnitude uint64
+	for i := 0; i < keep; i++ {
+		digit := digitAt(i)
+		if magnitude > (limit-digit)/10 {
+			return 0, 0, errors.New("amount value is out of range")
+		}
+		magnitude = magnitude*10 + digit
+	}
+
+	var first uint64 //the first discarded digit
+	sticky := false  //true if any discarded digit after the first is not 0
+	excessDigits := 0
+	for i := keep; i < len(integer)+len(fraction); i++ {
+		digit := digitAt(i)
+		if i == keep {
+			first = digit
+		} else if digit != 0 {
+			sticky = true
+		}
+		if digit != 0 {
+			excessDigits = i - keep + 1
+		}
+	}
+
+	roundUp := false
+	if first != 0 || sticky {
+		switch mode {
+		case RoundHalfUp:
+			roundUp = first >= 5
+		case RoundHalfDown:
+			roundUp = first > 5 || (first == 5 && sticky)
+		case RoundUp:
+			roundUp = true
+		case RoundDown:
+			roundUp = false
+		case RoundCeiling:
+			roundUp = !negative
+		case RoundFloor:
+			roundUp = negative
+		case RoundHalfEven:
+			roundUp = first > 5 || (first == 5 && (sticky || magnitude%2 == 1))
+		}
+	}
+	if roundUp {
+		if magnitude == limit {
+			return 0, 0, errors.New("amount value is out of range")
+		}
+		magnitude++
+	}
+	if negative {
+		return -int64(magnitude), excessDigits, nil //int64(1<<63) is math.MinInt64, its negation is itself
+	}
+	return int64(magnitude), excessDigits, nil
+}
+
+//maxDecimalExponent is the max absolute exponent accepted by parseBasicUnitValue
+const maxDecimalExponent = 1000
+
+//isDigits return true if all characters of text are decimal digits
+func isDigits(text string) bool {
+	for i := 0; i < len(text); i++ {
+		if text[i] < '0' || text[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+//ratFromFloat returns the exact value of the shortest decimal representation of float value (e.g.: 6.789)
+//return error if value is NaN or Inf
+func ratFromFloat(value float64) (*big.Rat, error) {
+	rat, ok := new(big.Rat).SetString(strconv.FormatFloat(value, 'f', -1, 64))
+	if !ok {
+		return nil, fmt.Errorf("%v is not a finite value", value)
+	}
+	return rat, nil
+}
+
+//ratFromMinorUnit returns the exact value in basic unit of minor unit value
+func ratFromMinorUnit(minorUnitValue int64, minorUnitDigits uint8) *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(minorUnitValue), pow10Int(minorUnitDigits))
+}
+
+//decimalString returns the exact decimal string of a terminating value, with at least minDigits fraction digits
+func decimalString(value *big.Rat, minDigits int) string {
+	digits := minDigits
+	scaled := new(big.Rat).Mul(value, new(big.Rat).SetInt(pow10Int(uint8(digits))))
+	ten := big.NewRat(10, 1)
+	for !scaled.IsInt() && digits < 255 {
+		scaled.Mul(scaled, ten)
+		digits++
+	}
+	return value.FloatString(digits)
+}
+
+//pow10Int returns 10^n
+func pow10Int(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
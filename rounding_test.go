@@ -0,0 +1,83 @@
+package currency
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func TestRoundRat(t *testing.T) {
+	values := []string{"2.5", "-2.5", "3.5", "2.4", "-2.6"}
+	wants := map[RoundingMode][]int64{
+		RoundHalfEven: {2, -2, 4, 2, -3},
+		RoundHalfUp:   {3, -3, 4, 2, -3},
+		RoundHalfDown: {2, -2, 3, 2, -3},
+		RoundUp:       {3, -3, 4, 3, -3},
+		RoundDown:     {2, -2, 3, 2, -2},
+		RoundCeiling:  {3, -2, 4, 3, -2},
+		RoundFloor:    {2, -3, 3, 2, -3},
+	}
+	for mode, want := range wants {
+		for i, value := range values {
+			rat, _ := new(big.Rat).SetString(value)
+			got := roundRat(rat, mode).Int64()
+			if got != want[i] {
+				t.Errorf("roundRat(%s, %s) == %d, want %d", value, mode, got, want[i])
+			}
+		}
+	}
+}
+
+func TestInvalidRoundingMode(t *testing.T) {
+	mode := RoundingMode(9)
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "100.05")
+	start := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	period := Period{start, start.AddDate(0, 0, 30)}
+	calls := map[string]func() error{
+		"NewAmountInBasicUnitWithMode": func() error {
+			_, err := Factory.NewAmountInBasicUnitWithMode("USD", "1.005", mode)
+			return err
+		},
+		"FxWithAudit": func() error {
+			_, err := usdAmount.FxWithAudit("CNY", RateQuote{Rate: 6.789}, mode)
+			return err
+		},
+		"Percent": func() error {
+			_, err := usdAmount.Percent(33, mode)
+			return err
+		},
+		"ApplyDiscount": func() error {
+			_, err := usdAmount.ApplyDiscount(10, mode)
+			return err
+		},
+		"Average": func() error {
+			_, _, err := Average([]Amount{usdAmount, usdAmount, usdAmount}, mode)
+			return err
+		},
+		"AddTax": func() error {
+			_, err := AddTax(usdAmount, []TaxRate{{Name: "VAT", Percent: 7}}, mode)
+			return err
+		},
+		"Loan.Schedule": func() error {
+			_, err := Loan{Principal: usdAmount, PeriodRate: 1, Periods: 3, Mode: mode}.Schedule()
+			return err
+		},
+		"Prorate": func() error {
+			_, _, err := usdAmount.Prorate(period, Period{start, start.AddDate(0, 0, 7)}, ProrateByDay, mode)
+			return err
+		},
+		"ProrateSplit": func() error {
+			_, err := usdAmount.ProrateSplit(period, ProrateByDay, mode, start.AddDate(0, 0, 7))
+			return err
+		},
+		"SimpleInterest": func() error {
+			_, err := usdAmount.SimpleInterest(5, start, period.End, Act365Fixed, mode)
+			return err
+		},
+	}
+	for name, call := range calls {
+		if err := call(); err == nil {
+			t.Errorf("%s with %s should be return an error, but no error return", name, mode)
+		}
+	}
+}
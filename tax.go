@@ -0,0 +1,181 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+//TaxRate is a tax levied on price (e.g.: VAT 20%)
+type TaxRate struct {
+	Name     string
+	Percent  float64 //the rate in percent, taken as its shortest decimal representation
+	Compound bool    //true if the tax is levied on net price plus the preceding taxes
+}
+
+//TaxRounding is where the taxes are rounded
+type TaxRounding uint8
+
+const (
+	//RoundPerLine rounds the taxes of each line, the invoice taxes are the sums of line taxes
+	RoundPerLine TaxRounding = iota
+	//RoundPerInvoice rounds the taxes of invoice total only
+	RoundPerInvoice
+)
+
+//TaxAmount is the amount of a tax rate
+type TaxAmount struct {
+	Name   string
+	Amount Amount
+}
+
+//TaxLine is the net, tax and gross of a price, Net + Tax == Gross exactly
+type TaxLine struct {
+	Net   Amount
+	Tax   Amount
+	Gross Amount
+	Taxes []TaxAmount //the tax of each rate, sums up to Tax
+}
+
+//TaxInvoice is the tax calculation of several prices
+type TaxInvoice struct {
+	TaxLine           //the invoice totals
+	Lines   []TaxLine //the tax calculation of each price, only reported when rounding per line
+}
+
+//TaxCalculator calculates taxes of tax-inclusive or tax-exclusive prices
+type TaxCalculator struct {
+	Rates     []TaxRate    //compound rates are levied on the preceding ones
+	Inclusive bool         //true if prices include taxes (gross), otherwise prices are net
+	Rounding  TaxRounding  //where the taxes are rounded
+	Mode      RoundingMode //the rounding mode of taxes
+}
+
+//AddTax returns the taxes and gross of net price
+//return error if any rate is negative, NaN or Inf
+func AddTax(net Amount, rates []TaxRate, mode RoundingMode) (TaxLine, error) {
+	return TaxCalculator{Rates: rates, Mode: mode}.calculateLine(net)
+}
+
+//ExtractTax returns the taxes and net of gross price
+//return error if any rate is negative, NaN or Inf
+func ExtractTax(gross Amount, rates []TaxRate, mode RoundingMode) (TaxLine, error) {
+	return TaxCalculator{Rates: rates, Inclusive: true, Mode: mode}.calculateLine(gross)
+}
+
+//Calculate returns the tax calculation of prices
+//return error if prices is empty, the currencies of prices are not same, or the sum overflows
+//return error if any rate is negative, NaN or Inf
+func (calculator TaxCalculator) Calculate(prices ...Amount) (TaxInvoice, error) {
+	total, err := Sum(prices)
+	if err != nil {
+		return TaxInvoice{}, err
+	}
+	if calculator.Rounding == RoundPerInvoice {
+		line, err := calculator.calculateLine(total)
+		return TaxInvoice{TaxLine: line}, err
+	}
+
+	var invoice TaxInvoice
+	for _, price := range prices {
+		line, err := calculator.calculateLine(price)
+		if err != nil {
+			return TaxInvoice{}, err
+		}
+		invoice.Lines = append(invoice.Lines, line)
+	}
+
+	invoice.Net, invoice.Tax, invoice.Gross = newZeroAmount(total.curreny), newZeroAmount(total.curreny), newZeroAmount(total.curreny)
+	for i, rate := range calculator.Rates {
+		invoice.Taxes = append(invoice.Taxes, TaxAmount{rate.Name, newZeroAmount(total.curreny)})
+		for _, line := range invoice.Lines {
+			if invoice.Taxes[i].Amount, err = invoice.Taxes[i].Amount.Add(line.Taxes[i].Amount); err != nil {
+				return TaxInvoice{}, err
+			}
+		}
+	}
+	for _, line := range invoice.Lines {
+		if invoice.Net, err = invoice.Net.Add(line.Net); err != nil {
+			return TaxInvoice{}, err
+		}
+		if invoice.Tax, err = invoice.Tax.Add(line.Tax); err != nil {
+			return TaxInvoice{}, err
+		}
+		if invoice.Gross, err = invoice.Gross.Add(line.Gross); err != nil {
+			return TaxInvoice{}, err
+		}
+	}
+	return invoice, nil
+}
+
+//calculateLine returns the tax calculation of a price, each tax is rounded by mode,
+//the gross (exclusive) or net (inclusive) is derived from the rounded taxes so that net + tax == gross
+func (calculator TaxCalculator) calculateLine(price Amount) (TaxLine, error) {
+	percents, factor, err := calculator.parseRates()
+	if err != nil {
+		return TaxLine{}, err
+	}
+	digits := price.curreny.MinorUnitDigits()
+	net := ratFromMinorUnit(price.minorUnitValue, digits)
+	if calculator.Inclusive {
+		net.Quo(net, factor)
+	}
+
+	line := TaxLine{Tax: newZeroAmount(price.curreny)}
+	preceding := new(big.Rat) //the sum of exact preceding taxes
+	for i, rate := range calculator.Rates {
+		base := new(big.Rat).Set(net)
+		if rate.Compound {
+			base.Add(base, preceding)
+		}
+		exact := base.Mul(base, percents[i])
+		preceding.Add(preceding, exact)
+
+		minorUnitValue, err := roundRatToMinorUnit(exact, digits, calculator.Mode)
+		if err != nil {
+			return TaxLine{}, err
+		}
+		tax := newZeroAmount(price.curreny)
+		tax.setMinorUnitValue(minorUnitValue)
+		line.Taxes = append(line.Taxes, TaxAmount{rate.Name, tax})
+		if line.Tax, err = line.Tax.Add(tax); err != nil {
+			return TaxLine{}, err
+		}
+	}
+
+	if calculator.Inclusive {
+		line.Gross = price
+		line.Net, err = price.Minus(line.Tax)
+	} else {
+		line.Net = price
+		line.Gross, err = price.Add(line.Tax)
+	}
+	if err != nil {
+		return TaxLine{}, err
+	}
+	return line, nil
+}
+
+//parseRates returns the exact fraction of each rate, and the factor of gross to net
+//return error if any rate is negative, NaN or Inf
+func (calculator TaxCalculator) parseRates() ([]*big.Rat, *big.Rat, error) {
+	percents := make([]*big.Rat, len(calculator.Rates))
+	taxes := new(big.Rat) //the sum of taxes of 1 net
+	for i, rate := range calculator.Rates {
+		percent, err := ratFromFloat(rate.Percent)
+		if err != nil {
+			return nil, nil, fmt.Errorf("tax rate %s: %v", rate.Name, err)
+		}
+		if percent.Sign() < 0 {
+			return nil, nil, errors.New("tax rate " + rate.Name + " can't be negative")
+		}
+		percents[i] = percent.Quo(percent, big.NewRat(100, 1))
+
+		tax := new(big.Rat).Set(percents[i])
+		if rate.Compound {
+			tax.Mul(tax, new(big.Rat).Add(big.NewRat(1, 1), taxes))
+		}
+		taxes.Add(taxes, tax)
+	}
+	return percents, taxes.Add(taxes, big.NewRat(1, 1)), nil
+}
@@ -0,0 +1,77 @@
+package currency
+
+import "testing"
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+}
+
+func TestAddTaxAndExtractTax(t *testing.T) {
+	vat := []TaxRate{{Name: "VAT", Percent: 20}}
+
+	//case 1:
+	net, _ := Factory.NewAmountInBasicUnit("USD", "10")
+	line, _ := AddTax(net, vat, RoundHalfEven)
+	if line.Tax.String() != "USD 2.00" || line.Gross.String() != "USD 12.00" {
+		t.Errorf("AddTax(%s, 20%%) == %s %s, want USD 2.00 USD 12.00", net.String(), line.Tax.String(), line.Gross.String())
+	}
+
+	//case 2:
+	gross, _ := Factory.NewAmountInBasicUnit("USD", "10")
+	line, _ = ExtractTax(gross, vat, RoundHalfEven)
+	if line.Net.String() != "USD 8.33" || line.Tax.String() != "USD 1.67" {
+		t.Errorf("ExtractTax(%s, 20%%) == %s %s, want USD 8.33 USD 1.67", gross.String(), line.Net.String(), line.Tax.String())
+	}
+
+	//case 3:
+	_, err := AddTax(net, []TaxRate{{Name: "VAT", Percent: -1}}, RoundHalfEven)
+	if err == nil {
+		t.Errorf("AddTax(%s, -1%%) should be return an error, but no error return", net.String())
+	}
+}
+
+func TestCompoundTax(t *testing.T) {
+	rates := []TaxRate{{Name: "A", Percent: 10}, {Name: "B", Percent: 10, Compound: true}}
+
+	//case 1:
+	net, _ := Factory.NewAmountInBasicUnit("USD", "100")
+	line, _ := AddTax(net, rates, RoundHalfEven)
+	if line.Taxes[0].Amount.String() != "USD 10.00" || line.Taxes[1].Amount.String() != "USD 11.00" || line.Gross.String() != "USD 121.00" {
+		t.Errorf("AddTax(%s, 10%%, 10%% compound) == %v, want USD 10.00 USD 11.00 USD 121.00", net.String(), line)
+	}
+
+	//case 2:
+	line, _ = ExtractTax(line.Gross, rates, RoundHalfEven)
+	if line.Net.String() != "USD 100.00" || line.Taxes[1].Amount.String() != "USD 11.00" {
+		t.Errorf("ExtractTax(USD 121.00, 10%%, 10%% compound) == %v, want net USD 100.00", line)
+	}
+}
+
+func TestTaxCalculatorRounding(t *testing.T) {
+	price, _ := Factory.NewAmountInBasicUnit("USD", "0.05")
+	calculator := TaxCalculator{Rates: []TaxRate{{Name: "VAT", Percent: 10}}, Mode: RoundHalfEven}
+
+	//case 1:
+	invoice, _ := calculator.Calculate(price, price, price)
+	if invoice.Tax.String() != "USD 0.00" || len(invoice.Lines) != 3 {
+		t.Errorf("Calculate() per line == %s with %d lines, want USD 0.00 with 3 lines", invoice.Tax.String(), len(invoice.Lines))
+	}
+
+	//case 2:
+	calculator.Rounding = RoundPerInvoice
+	invoice, _ = calculator.Calculate(price, price, price)
+	if invoice.Tax.String() != "USD 0.02" || invoice.Gross.String() != "USD 0.17" {
+		t.Errorf("Calculate() per invoice == %s %s, want USD 0.02 USD 0.17", invoice.Tax.String(), invoice.Gross.String())
+	}
+
+	//case 3: net + tax == gross
+	calculator = TaxCalculator{Rates: []TaxRate{{Name: "GST", Percent: 5}, {Name: "QST", Percent: 9.975}}, Inclusive: true}
+	for _, value := range []string{"0.01", "0.99", "17.23", "999.99"} {
+		gross, _ := Factory.NewAmountInBasicUnit("USD", value)
+		invoice, _ = calculator.Calculate(gross, gross)
+		sum, _ := invoice.Net.Add(invoice.Tax)
+		if !sum.IsEquals(invoice.Gross) {
+			t.Errorf("Calculate(%s) net %s + tax %s != gross %s", gross.String(), invoice.Net.String(), invoice.Tax.String(), invoice.Gross.String())
+		}
+	}
+}
@@ -0,0 +1,193 @@
+package currency
+
+import (
+	"strconv"
+	"strings"
+)
+
+//the names of built-in rules
+const (
+	RuleFormat            = "format"             //the currency or value of input is invalid
+	RuleAllowedCurrencies = "allowed_currencies" //the currency is not allowed
+	RuleMin               = "min"                //the amount is less than the min of its currency
+	RuleMax               = "max"                //the amount is greater than the max of its currency
+	RuleNonNegative       = "non_negative"       //the amount is negative
+	RuleMaxDecimals       = "max_decimals"       //the input has more decimals than the minor unit digits of currency
+)
+
+//Violation is a rule violated by an amount
+type Violation struct {
+	Rule     string `json:"rule"`            //the name of rule (e.g.: max_decimals)
+	Currency string `json:"currency"`        //the currency code of amount
+	Value    string `json:"value"`           //the raw input value if any, otherwise the basic unit value of amount
+	Limit    string `json:"limit,omitempty"` //the limit of rule (e.g.: 1000.00 of max, 2 of max_decimals)
+	Message  string `json:"message"`
+}
+
+//ValidationError is the error of Validator, reports all violations of an amount
+type ValidationError struct {
+	Violations []Violation
+}
+
+//Error implements error
+func (err *ValidationError) Error() string {
+	messages := make([]string, len(err.Violations))
+	for i, violation := range err.Violations {
+		messages[i] = violation.Rule + ": " + violation.Message
+	}
+	return "amount is invalid: " + strings.Join(messages, "; ")
+}
+
+//AmountInput is the raw input of an amount before rounding (e.g.: usd, 1.567)
+type AmountInput struct {
+	CurrencyCode   string
+	BasicUnitValue string
+}
+
+//Rule checks an amount and returns the violation, or nil if the amount satisfies the rule.
+//input is the raw input of amount, empty if amount is not validated from raw input
+type Rule func(amount Amount, input AmountInput) *Violation
+
+//Validator validates amounts by rules, all rules are checked and all violations are reported
+type Validator struct {
+	rules []Rule
+}
+
+//NewValidator create a new validator of rules
+func NewValidator(rules ...Rule) *Validator {
+	return &Validator{rules: append([]Rule(nil), rules...)}
+}
+
+//With returns a new validator of the rules of validator and rules, validator is not changed
+func (validator *Validator) With(rules ...Rule) *Validator {
+	return NewValidator(append(append([]Rule(nil), validator.rules...), rules...)...)
+}
+
+//Validate validates amount, the rules of raw input (e.g.: MaxDecimals) are satisfied
+//return *ValidationError if any rule is violated
+func (validator *Validator) Validate(amount Amount) error {
+	return validator.validate(amount, AmountInput{})
+}
+
+//ValidateInput validates the raw input and returns the amount created by Factory with banker's rounding,
+//even if Factory has option WithStrictPrecision, so that excess decimals are reported by MaxDecimals
+//return *ValidationError with RuleFormat violation if the currency or value is invalid
+//return *ValidationError if any rule is violated, the amount is returned with it
+func (validator *Validator) ValidateInput(currencyCode string, basicUnitValue string) (Amount, error) {
+	input := AmountInput{currencyCode, basicUnitValue}
+	amount, err := Factory.newAmountInBasicUnit(currencyCode, basicUnitValue, RoundHalfEven, false)
+	if err != nil {
+		violation := Violation{RuleFormat, strings.ToUpper(strings.TrimSpace(currencyCode)), strings.TrimSpace(basicUnitValue), "", err.Error()}
+		return Amount{}, &ValidationError{[]Violation{violation}}
+	}
+	return amount, validator.validate(amount, input)
+}
+
+//validate checks all rules
+func (validator *Validator) validate(amount Amount, input AmountInput) error {
+	var violations []Violation
+	for _, rule := range validator.rules {
+		if violation := rule(amount, input); violation != nil {
+			violations = append(violations, *violation)
+		}
+	}
+	if len(violations) > 0 {
+		return &ValidationError{violations}
+	}
+	return nil
+}
+
+//AllowedCurrencies is the rule that the currency of amount is one of currencyCodes
+func AllowedCurrencies(currencyCodes ...string) Rule {
+	allowed := make([]string, len(currencyCodes))
+	for i, code := range currencyCodes {
+		allowed[i] = strings.ToUpper(strings.TrimSpace(code))
+	}
+	return func(amount Amount, input AmountInput) *Violation {
+		for _, code := range allowed {
+			if code == amount.curreny.code {
+				return nil
+			}
+		}
+		return newViolation(RuleAllowedCurrencies, amount, input, strings.Join(allowed, ","),
+			amount.curreny.code+" is not in allowed currencies "+strings.Join(allowed, ", "))
+	}
+}
+
+//Min is the rule that amount is not less than the min of its currency, amounts of other currencies are not limited
+func Min(mins ...Amount) Rule {
+	return func(amount Amount, input AmountInput) *Violation {
+		for _, min := range mins {
+			if min.curreny.code == amount.curreny.code && amount.minorUnitValue < min.minorUnitValue {
+				return newViolation(RuleMin, amount, input, min.BasicUnitValue(), amount.String()+" is less than min "+min.String())
+			}
+		}
+		return nil
+	}
+}
+
+//Max is the rule that amount is not greater than the max of its currency, amounts of other currencies are not limited
+func Max(maxes ...Amount) Rule {
+	return func(amount Amount, input AmountInput) *Violation {
+		for _, max := range maxes {
+			if max.curreny.code == amount.curreny.code && amount.minorUnitValue > max.minorUnitValue {
+				return newViolation(RuleMax, amount, input, max.BasicUnitValue(), amount.String()+" is greater than max "+max.String())
+			}
+		}
+		return nil
+	}
+}
+
+//NonNegative is the rule that amount is not less than 0
+func NonNegative() Rule {
+	return func(amount Amount, input AmountInput) *Violation {
+		negative := amount.minorUnitValue < 0
+		if input.BasicUnitValue != "" {
+			negative = strings.HasPrefix(strings.TrimSpace(input.BasicUnitValue), "-") && !isZeroDecimal(input.BasicUnitValue)
+		}
+		if negative {
+			return newViolation(RuleNonNegative, amount, input, "0", amount.curreny.code+" "+violationValue(amount, input)+" is negative")
+		}
+		return nil
+	}
+}
+
+//MaxDecimals is the rule that the raw input has no more decimals than the minor unit digits of currency
+//(e.g.: USD 1.567 violates it instead of being rounded to 1.57), trailing zeros are not counted (e.g.: USD 1.500)
+func MaxDecimals() Rule {
+	return func(amount Amount, input AmountInput) *Violation {
+		if input.BasicUnitValue == "" {
+			return nil
+		}
+		digits := amount.curreny.minorUnitDigits
+		_, excessDigits, err := parseBasicUnitValue(strings.TrimSpace(input.BasicUnitValue), digits, RoundHalfEven)
+		if err != nil || excessDigits == 0 {
+			return nil
+		}
+		return newViolation(RuleMaxDecimals, amount, input, strconv.Itoa(int(digits)),
+			amount.curreny.code+" "+violationValue(amount, input)+" has "+strconv.Itoa(int(digits)+excessDigits)+
+				" decimals, more than "+strconv.Itoa(int(digits))+" of "+amount.curreny.code)
+	}
+}
+
+//newViolation create a new violation of amount
+func newViolation(rule string, amount Amount, input AmountInput, limit string, message string) *Violation {
+	return &Violation{rule, amount.curreny.code, violationValue(amount, input), limit, message}
+}
+
+//violationValue returns the raw input value if any, otherwise the basic unit value of amount
+func violationValue(amount Amount, input AmountInput) string {
+	if value := strings.TrimSpace(input.BasicUnitValue); value != "" {
+		return value
+	}
+	return amount.BasicUnitValue()
+}
+
+//isZeroDecimal return true if all digits of the decimal text are 0 (e.g.: -0.00)
+func isZeroDecimal(text string) bool {
+	mantissa := strings.TrimSpace(text)
+	if i := strings.IndexAny(mantissa, "eE"); i >= 0 {
+		mantissa = mantissa[:i]
+	}
+	return strings.Trim(mantissa, "+-0.") == ""
+}
@@ -0,0 +1,91 @@
+package currency
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+	Factory.NewCurrency("JPY", 0)
+}
+
+//violatedRules returns the rules of violations in err
+func violatedRules(err error) []string {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return nil
+	}
+	rules := make([]string, len(validationErr.Violations))
+	for i, violation := range validationErr.Violations {
+		rules[i] = violation.Rule
+	}
+	return rules
+}
+
+func TestValidateInput(t *testing.T) {
+	maxUsd, _ := Factory.NewAmountInBasicUnit("USD", "1000")
+	maxJpy, _ := Factory.NewAmountInBasicUnit("JPY", "100000")
+	validator := NewValidator(AllowedCurrencies("usd", "JPY"), NonNegative(), MaxDecimals(), Max(maxUsd, maxJpy))
+
+	cases := []struct {
+		currencyCode   string
+		basicUnitValue string
+		want           []string
+	}{
+		{"usd", "999.99", nil},
+		{"USD", "1.500", nil},
+		{"USD", "1.567", []string{RuleMaxDecimals}},
+		{"USD", "-1000.001", []string{RuleNonNegative, RuleMaxDecimals}},
+		{"USD", "-0.001", []string{RuleNonNegative, RuleMaxDecimals}},
+		{"JPY", "100001.5", []string{RuleMaxDecimals, RuleMax}},
+		{"CNY", "1", []string{RuleAllowedCurrencies}},
+		{"ABC", "1", []string{RuleFormat}},
+		{"USD", "1,00", []string{RuleFormat}},
+	}
+	for _, c := range cases {
+		_, err := validator.ValidateInput(c.currencyCode, c.basicUnitValue)
+		if got := violatedRules(err); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ValidateInput(%q, %q) == %v, want %v", c.currencyCode, c.basicUnitValue, got, c.want)
+		}
+	}
+
+	//case: structured violation
+	_, err := validator.ValidateInput("USD", "1.567")
+	want := Violation{RuleMaxDecimals, "USD", "1.567", "2", "USD 1.567 has 3 decimals, more than 2 of USD"}
+	if validationErr, ok := err.(*ValidationError); !ok || validationErr.Violations[0] != want {
+		t.Errorf("ValidateInput(\"USD\", \"1.567\") == %v, want %v", err, want)
+	}
+
+	//case: structured violation with strict Factory
+	Factory.SetOptions(WithStrictPrecision())
+	defer func() { Factory.strictPrecision = false }()
+	_, err = validator.ValidateInput("USD", "1.567")
+	if validationErr, ok := err.(*ValidationError); !ok || len(validationErr.Violations) != 1 || validationErr.Violations[0] != want {
+		t.Errorf("ValidateInput(\"USD\", \"1.567\") with strict Factory == %v, want %v", err, want)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	minUsd, _ := Factory.NewAmountInBasicUnit("USD", "1")
+	validator := NewValidator(Min(minUsd))
+	strictValidator := validator.With(AllowedCurrencies("USD"))
+
+	//case 1:
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "0.5")
+	if err := validator.Validate(cnyAmount); err != nil {
+		t.Errorf("Validate(%s) == %v, want nil", cnyAmount.String(), err)
+	}
+	if got := violatedRules(strictValidator.Validate(cnyAmount)); !reflect.DeepEqual(got, []string{RuleAllowedCurrencies}) {
+		t.Errorf("With(AllowedCurrencies(\"USD\")).Validate(%s) == %v, want [%s]", cnyAmount.String(), got, RuleAllowedCurrencies)
+	}
+
+	//case 2:
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "0.99")
+	err := validator.Validate(usdAmount)
+	if err == nil || err.Error() != "amount is invalid: min: USD 0.99 is less than min USD 1.00" {
+		t.Errorf("Validate(%s) == %v, want min violation", usdAmount.String(), err)
+	}
+}
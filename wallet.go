@@ -0,0 +1,197 @@
+package currency
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+//Wallet is a money bag holds balances in several currencies, balances of zero are not kept.
+//The zero value of Wallet is an empty wallet ready to use
+type Wallet struct {
+	balances map[string]Amount //key is ISO 4217 three-letter alphabetic code
+}
+
+//NewWallet create a new wallet with initial amounts
+//return error if any amount has no currency
+func NewWallet(amounts ...Amount) (*Wallet, error) {
+	wallet := &Wallet{}
+	for _, amount := range amounts {
+		if err := wallet.Add(amount); err != nil {
+			return nil, err
+		}
+	}
+	return wallet, nil
+}
+
+//Add add amount to the balance of its currency
+//return error if amount has no currency
+//return error if the balance overflows, the balance is not changed
+func (wallet *Wallet) Add(amount Amount) error {
+	code := amount.curreny.Code()
+	if code == "" {
+		return errors.New("Wallet add fail: amount has no currency")
+	}
+
+	balance, err := wallet.balance(amount.curreny).Add(amount)
+	if err != nil {
+		return err
+	}
+	wallet.setBalance(balance)
+	return nil
+}
+
+//Subtract subtract amount from the balance of its currency, the balance may become negative
+//return error if amount has no currency
+//return error if the balance overflows, the balance is not changed
+func (wallet *Wallet) Subtract(amount Amount) error {
+	code := amount.curreny.Code()
+	if code == "" {
+		return errors.New("Wallet subtract fail: amount has no currency")
+	}
+
+	balance, err := wallet.balance(amount.curreny).Minus(amount)
+	if err != nil {
+		return err
+	}
+	wallet.setBalance(balance)
+	return nil
+}
+
+//AddWallet add all balances of other wallet, nil other is taken as an empty wallet
+//return error if any balance overflows, wallet is not changed
+func (wallet *Wallet) AddWallet(other *Wallet) error {
+	return wallet.combine(other, Amount.Add)
+}
+
+//SubtractWallet subtract all balances of other wallet, nil other is taken as an empty wallet
+//return error if any balance overflows, wallet is not changed
+func (wallet *Wallet) SubtractWallet(other *Wallet) error {
+	return wallet.combine(other, Amount.Minus)
+}
+
+//combine computes all balances of wallet and other by operation before changing wallet, so that it's all or nothing
+func (wallet *Wallet) combine(other *Wallet, operation func(Amount, Amount) (Amount, error)) error {
+	if other == nil {
+		return nil
+	}
+	amounts := other.Amounts()
+	balances := make([]Amount, len(amounts))
+	for i, amount := range amounts {
+		balance, err := operation(wallet.balance(amount.curreny), amount)
+		if err != nil {
+			return err
+		}
+		balances[i] = balance
+	}
+	for _, balance := range balances {
+		wallet.setBalance(balance)
+	}
+	return nil
+}
+
+//Balance returns the balance of currency, zero if wallet has no balance of it
+//return error if currencyCode is not a three-letter alphabetic code
+//return error if currencyCode is not managed by factory
+func (wallet *Wallet) Balance(currencyCode string) (Amount, error) {
+	currencyCode = strings.ToUpper(strings.TrimSpace(currencyCode))
+	if balance, exists := wallet.balances[currencyCode]; exists {
+		return balance, nil
+	}
+	currency, err := Factory.GetCurrencyByCode(currencyCode)
+	if err != nil {
+		return Amount{}, err
+	}
+	return newZeroAmount(currency), nil
+}
+
+//Currencies returns the sorted currency codes of non-zero balances
+func (wallet *Wallet) Currencies() []string {
+	codes := make([]string, 0, len(wallet.balances))
+	for code := range wallet.balances {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+//Amounts returns the non-zero balances sorted by currency code
+func (wallet *Wallet) Amounts() []Amount {
+	codes := wallet.Currencies()
+	amounts := make([]Amount, len(codes))
+	for i, code := range codes {
+		amounts[i] = wallet.balances[code]
+	}
+	return amounts
+}
+
+//Each calls fn for each non-zero balance sorted by currency code, stops if fn returns false
+func (wallet *Wallet) Each(fn func(amount Amount) bool) {
+	for _, amount := range wallet.Amounts() {
+		if !fn(amount) {
+			return
+		}
+	}
+}
+
+//IsEmpty return true if wallet has no non-zero balance
+func (wallet *Wallet) IsEmpty() bool {
+	return len(wallet.balances) == 0
+}
+
+//Total returns the valuation of all balances in target currency through the rate source,
+//each balance is converted and rounded before summing up
+//return error if source can't provide the rate of any balance
+//return error if targetCurrencyCode is not managed by factory
+func (wallet *Wallet) Total(targetCurrencyCode string, source RateSource) (Amount, error) {
+	targetCurrency, err := Factory.GetCurrencyByCode(targetCurrencyCode)
+	if err != nil {
+		return Amount{}, err
+	}
+
+	total := newZeroAmount(targetCurrency)
+	for _, amount := range wallet.Amounts() {
+		converted, err := amount.FxByRateSource(targetCurrency.Code(), source)
+		if err != nil {
+			return Amount{}, err
+		}
+		if total, err = total.Add(converted); err != nil {
+			return Amount{}, err
+		}
+	}
+	return total, nil
+}
+
+//String returns default format string of wallet(e.g.: [CNY 6.80, USD 1.00])
+func (wallet *Wallet) String() string {
+	var builder strings.Builder
+	builder.WriteString("[")
+	for i, amount := range wallet.Amounts() {
+		if i > 0 {
+			builder.WriteString(", ")
+		}
+		builder.WriteString(amount.String())
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
+
+//balance returns the balance of currency, zero if wallet has no balance of it
+func (wallet *Wallet) balance(currency Currency) Amount {
+	if balance, exists := wallet.balances[currency.Code()]; exists {
+		return balance
+	}
+	return newZeroAmount(currency)
+}
+
+//setBalance set the balance of currency, removes it if zero
+func (wallet *Wallet) setBalance(balance Amount) {
+	if balance.minorUnitValue == 0 {
+		delete(wallet.balances, balance.curreny.Code())
+		return
+	}
+	if wallet.balances == nil {
+		wallet.balances = make(map[string]Amount)
+	}
+	wallet.balances[balance.curreny.Code()] = balance
+}
@@ -0,0 +1,104 @@
+package currency
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func init() {
+	Factory.NewCurrency("USD", 2)
+	Factory.NewCurrency("CNY", 2)
+}
+
+func TestWalletAddSubtract(t *testing.T) {
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "1.5")
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "6.8")
+	wallet, _ := NewWallet(usdAmount, cnyAmount, usdAmount)
+
+	//case 1:
+	got, _ := wallet.Balance("usd")
+	want, _ := Factory.NewAmountInBasicUnit("USD", "3")
+	if !want.IsEquals(got) {
+		t.Errorf("%s Balance(\"usd\") == %s, want %s", wallet.String(), got.String(), want.String())
+	}
+
+	//case 2:
+	wallet.Subtract(cnyAmount)
+	if !reflect.DeepEqual(wallet.Currencies(), []string{"USD"}) {
+		t.Errorf("%s Currencies() == %v, want [USD]", wallet.String(), wallet.Currencies())
+	}
+	got, _ = wallet.Balance("CNY")
+	if got.MinorUnitValue() != 0 || got.CurrencyCode() != "CNY" {
+		t.Errorf("%s Balance(\"CNY\") == %s, want CNY 0.00", wallet.String(), got.String())
+	}
+
+	//case 3:
+	other, _ := NewWallet(cnyAmount, usdAmount)
+	wallet.SubtractWallet(other)
+	if wallet.String() != "[CNY -6.80, USD 1.50]" {
+		t.Errorf("SubtractWallet() == %s, want [CNY -6.80, USD 1.50]", wallet.String())
+	}
+
+	//case 4:
+	err := wallet.Add(Amount{})
+	if err == nil {
+		t.Errorf("%s Add(Amount{}) should be return an error, but no error return", wallet.String())
+	}
+
+	//case 5: zero value
+	var zero Wallet
+	if err := zero.Add(usdAmount); err != nil || zero.String() != "[USD 1.50]" {
+		t.Errorf("Wallet{} Add(%s) == %s %v, want [USD 1.50]", usdAmount.String(), zero.String(), err)
+	}
+
+	//case 6: nil other wallet is empty
+	if err := zero.AddWallet(nil); err != nil || zero.String() != "[USD 1.50]" {
+		t.Errorf("AddWallet(nil) == %s %v, want [USD 1.50]", zero.String(), err)
+	}
+	if err := zero.SubtractWallet(nil); err != nil || zero.String() != "[USD 1.50]" {
+		t.Errorf("SubtractWallet(nil) == %s %v, want [USD 1.50]", zero.String(), err)
+	}
+}
+
+func TestWalletOverflow(t *testing.T) {
+	maxAmount, _ := Factory.NewAmountInMinorUnit("USD", math.MaxInt64-50)
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "1")
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "1")
+	wallet, _ := NewWallet(maxAmount)
+
+	//case 1:
+	err := wallet.Add(usdAmount)
+	got, _ := wallet.Balance("USD")
+	if err == nil || !got.IsEquals(maxAmount) {
+		t.Errorf("%s Add(%s) == %v, want an error and balance not changed", wallet.String(), usdAmount.String(), err)
+	}
+
+	//case 2: all or nothing
+	other, _ := NewWallet(cnyAmount, usdAmount)
+	err = wallet.AddWallet(other)
+	if err == nil || wallet.String() != "[USD "+maxAmount.BasicUnitValue()+"]" {
+		t.Errorf("AddWallet() == %s %v, want an error and wallet not changed", wallet.String(), err)
+	}
+}
+
+func TestWalletTotal(t *testing.T) {
+	table := NewRateTable("")
+	table.SetRate("USD", "CNY", 8)
+	usdAmount, _ := Factory.NewAmountInBasicUnit("USD", "1.5")
+	cnyAmount, _ := Factory.NewAmountInBasicUnit("CNY", "6")
+	wallet, _ := NewWallet(usdAmount, cnyAmount)
+
+	//case 1:
+	got, _ := wallet.Total("usd", table)
+	want, _ := Factory.NewAmountInBasicUnit("USD", "2.25")
+	if !want.IsEquals(got) {
+		t.Errorf("%s Total(\"usd\") == %s, want %s", wallet.String(), got.String(), want.String())
+	}
+
+	//case 2:
+	_, err := wallet.Total("usd", NewRateTable(""))
+	if err == nil {
+		t.Errorf("%s Total(\"usd\") without rates should be return an error, but no error return", wallet.String())
+	}
+}